@@ -20,13 +20,19 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -39,48 +45,141 @@ const (
 	encodeQueryComponent
 )
 
+// pageSize is the number of objects requested per page from paginated API endpoints.
+const pageSize = 100
+
 // Credentials contains the parameters used to authenticate against the Rubrik cluster and can be consumed
-// through ConnectEnv() or Connect().
+// through ConnectEnv() or Connect(). When "APIToken" is populated it is used in place of the "Username" and "Password".
+//
+// The optional "BeforeRequest" hook is called with every request immediately before it is sent to the Rubrik cluster and the optional
+// "AfterResponse" hook with every response received along with the duration of the call. "AfterResponse" must not read or close
+// the response body. Request counts and latencies are reported to "Metrics" when it is populated.
+//
+// "DefaultTimeout" is the HTTP timeout, in seconds, used by every function when a per-call timeout is not provided. "DialTimeout"
+// optionally limits, in seconds, how long establishing a connection to the Rubrik cluster may take so that calls to an unreachable
+// node fail quickly while slow requests can still use a long timeout.
+//
+// "Version" contains the software version of the Rubrik cluster and is populated by Connect().
+//
+// Clusters that are reached through a reverse proxy or gateway can override the "Scheme" (https by default), "Port" (the default
+// port of the scheme by default), and "BasePath" prepended to every API path (ex: /rubrik01 for https://{NodeIP}/rubrik01/api/v1).
+//
+// When "ReAuthenticate" is true and the Rubrik cluster rejects a request with a 401 Unauthorized status, such as when an API token
+// expires, a new session token is requested with the "Username" and "Password" and the request is retried once. The request is
+// not retried when no username is available or the new session can not be created.
+//
+// Requests that are rate limited by the Rubrik cluster (429 Too Many Requests), and GET requests that fail with a 5xx status, are
+// retried up to "MaxRetries" times (3 by default, use a negative value to disable retries). Each retry waits for the delay
+// requested by the Retry-After header of the response or, when it is not provided, an exponential backoff starting at 1 second.
+//
+// When "PreserveNumbers" is true, numbers in API responses are decoded as json.Number instead of float64 so that large values,
+// such as byte counts, keep their full precision. Use Int64Value() or Float64Value() to read a number from an API response
+// regardless of how it was decoded.
+//
+// When "DryRun" is true, POST, PATCH, PUT, and DELETE requests are logged, with any sensitive fields redacted, instead of being sent
+// to the Rubrik cluster and a synthetic {"statusCode": 200, "dryRun": true} response is returned. GET requests are still sent so
+// that lookups continue to work. Functions that depend on the response of a change, such as waiting for a job, may return an error
+// during a dry run.
+//
+// Functions that wait for an asynchronous job, such as WaitForJob(), check the status of the job every "JobPollInterval" (5 seconds by
+// default) and return an error if the job has not completed within "JobMaxWait" (2 hours by default).
 type Credentials struct {
-	NodeIP   string
-	Username string
-	Password string
+	NodeIP          string
+	Username        string
+	Password        string
+	APIToken        string
+	DefaultTimeout  int
+	DialTimeout     int
+	DryRun          bool
+	ReAuthenticate  bool
+	MaxRetries      int
+	PreserveNumbers bool
+	JobPollInterval time.Duration
+	JobMaxWait      time.Duration
+	Version         string
+
+	Scheme   string
+	Port     int
+	BasePath string
+
+	BeforeRequest func(*http.Request)
+	AfterResponse func(*http.Response, time.Duration)
+	Metrics       MetricsCollector
+
+	logger *log.Logger
 }
 
 // Connect initializes a new API client based on manually provided Rubrik cluster credentials. When possible,
 // the Rubrik credentials should not be stored as plain text in your .go file. ConnectEnv() can be used
 // as a safer alternative.
-func Connect(nodeIP, username, password string) *Credentials {
+//
+// The credentials are validated with a GET /v1/cluster/me call and an error is returned if the Rubrik cluster can not be reached
+// or rejects the credentials. The version of the Rubrik cluster is stored in the "Version" field of the returned Credentials. When
+// the "username" and "password" are blank strings, such as when connecting to a node to Bootstrap() the cluster, the
+// credentials are not validated.
+func Connect(nodeIP, username, password string) (*Credentials, error) {
 	client := &Credentials{
 		NodeIP:   nodeIP,
 		Username: username,
 		Password: password,
 	}
 
+	if len(username) == 0 && len(password) == 0 {
+		return client, nil
+	}
+
+	clusterSummary, err := client.commonAPI("GET", "v1", "/cluster/me", nil, client.httpTimeout(nil))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to connect to the Rubrik cluster '%s': %s", nodeIP, err)
+	}
+
+	client.Version, err = getString(clusterSummary, "version")
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// ConnectAPIToken initializes a new API client that authenticates against the Rubrik cluster with an existing API token instead
+// of a username and password.
+func ConnectAPIToken(nodeIP, apiToken string) *Credentials {
+	client := &Credentials{
+		NodeIP:   nodeIP,
+		APIToken: apiToken,
+	}
+
 	return client
 }
 
 // ConnectEnv is the preferred method to initialize a new API client by attempting to read the
 // following environment variables:
 //
-//  rubrik_cdm_node_ip
+//	rubrik_cdm_node_ip
 //
-//  rubrik_cdm_username
+//	rubrik_cdm_username
 //
-//  rubrik_cdm_password
-func ConnectEnv() *Credentials {
+//	rubrik_cdm_password
+//
+//	rubrik_cdm_token (Optional. When present it is used in place of the username and password)
+func ConnectEnv() (*Credentials, error) {
 
 	nodeIP, ok := os.LookupEnv("rubrik_cdm_node_ip")
 	if ok != true {
-		log.Fatalf("Error: The `rubrik_cdm_node_ip` environment variable is not present.")
+		return nil, errors.New("The `rubrik_cdm_node_ip` environment variable is not present.")
+	}
+
+	if apiToken, ok := os.LookupEnv("rubrik_cdm_token"); ok {
+		return ConnectAPIToken(nodeIP, apiToken), nil
 	}
+
 	username, ok := os.LookupEnv("rubrik_cdm_username")
 	if ok != true {
-		log.Fatalf("Error: The `rubrik_cdm_username` environment variable is not present.")
+		return nil, errors.New("The `rubrik_cdm_username` environment variable is not present.")
 	}
 	password, ok := os.LookupEnv("rubrik_cdm_password")
 	if ok != true {
-		log.Fatalf("Error: The `rubrik_cdm_password` environment variable is not present.")
+		return nil, errors.New("The `rubrik_cdm_password` environment variable is not present.")
 	}
 
 	client := &Credentials{
@@ -89,95 +188,472 @@ func ConnectEnv() *Credentials {
 		Password: password,
 	}
 
-	return client
+	return client, nil
 }
 
-// Consolidate the base API functions.
-func (c *Credentials) commonAPI(callType, apiVersion, apiEndpoint string, config interface{}, timeout int) interface{} {
+// APIError is returned when the Rubrik cluster responds to an API call with an error. "StatusCode" and "Status" contain the HTTP
+// status of the response and "Message" the error message provided by the Rubrik cluster, if any. "RetryAfter" contains the delay
+// requested by the Retry-After header of the response, if any.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	RetryAfter time.Duration
+}
 
-	if apiVersionValidation(apiVersion) == false {
-		log.Fatalf("Error: Enter a valid API version.")
+func (e *APIError) Error() string {
+	if len(e.Message) != 0 {
+		return e.Message
 	}
+	return e.Status
+}
 
-	if endpointValidation(apiEndpoint) == "errorStart" {
-		log.Fatalf("Error: The API Endpoint should begin with '/' (ex: /cluster/me).")
-	} else if endpointValidation(apiEndpoint) == "errorEnd" {
-		log.Fatal("Error: The API Endpoint should not end with '/' (ex. /cluster/me).")
+// SetLogger enables logging of the method, URL, status code, and duration of every API call made to the Rubrik cluster. Request bodies
+// are also logged with the value of any password, secret, token, or key field redacted. The Authorization header is never logged.
+// Use nil to disable logging.
+func (c *Credentials) SetLogger(logger *log.Logger) {
+	c.logger = logger
+}
+
+// logDryRun logs the request that would have been sent to the Rubrik cluster if "DryRun" was false.
+func (c *Credentials) logDryRun(callType, requestURL string, body []byte) {
+
+	payload := "none"
+	if len(body) != 0 {
+		payload = redactRequestBody(body)
+	}
+
+	c.logf("[dry run] %s %s request body: %s", callType, requestURL, payload)
+}
+
+// logf writes to the logger provided to SetLogger() when present, otherwise the standard logger.
+func (c *Credentials) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	} else {
+		log.Printf(format, v...)
+	}
+}
+
+// redactRequestBody returns the JSON encoded "body" with the value of any sensitive field replaced.
+func redactRequestBody(body []byte) string {
+
+	var config interface{}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return "[REDACTED]"
 	}
 
+	redacted, err := json.Marshal(redactValue(config))
+	if err != nil {
+		return "[REDACTED]"
+	}
+
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range v {
+			field := strings.ToLower(key)
+			if strings.Contains(field, "password") || strings.Contains(field, "secret") || strings.Contains(field, "token") || strings.Contains(field, "key") {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactValue(fieldValue)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+
+	return value
+}
+
+// baseURL returns the scheme, host, and base path that every API path is appended to (ex: https://{NodeIP}).
+func (c *Credentials) baseURL() string {
+
+	scheme := c.Scheme
+	if len(scheme) == 0 {
+		scheme = "https"
+	}
+
+	host := c.NodeIP
+	if c.Port > 0 {
+		host = net.JoinHostPort(c.NodeIP, strconv.Itoa(c.Port))
+	}
+
+	basePath := strings.TrimSuffix(c.BasePath, "/")
+	if len(basePath) != 0 && strings.HasPrefix(basePath, "/") == false {
+		basePath = "/" + basePath
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, host, basePath)
+}
+
+// httpClient returns the HTTP client used to communicate with the Rubrik cluster. The "timeout" limits the entire request while the
+// "DialTimeout", when set, only limits establishing the connection.
+func (c *Credentials) httpClient(timeout int) *http.Client {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
-	client := &http.Client{
+
+	if c.DialTimeout > 0 {
+		dialer := &net.Dialer{Timeout: time.Second * time.Duration(c.DialTimeout)}
+		tr.DialContext = dialer.DialContext
+	}
+
+	return &http.Client{
 		Transport: tr,
 		Timeout:   time.Second * time.Duration(timeout),
 	}
+}
+
+// authorize adds the API token, or the username and password if no token is present, to the request.
+func (c *Credentials) authorize(request *http.Request) {
+	if len(c.APIToken) != 0 {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIToken))
+	} else if len(c.Username) != 0 {
+		request.SetBasicAuth(c.Username, c.Password)
+	}
+}
+
+// Consolidate the base API functions.
+func (c *Credentials) commonAPI(callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (interface{}, error) {
+
+	apiResponse, err := c.retryRequest(callType, apiVersion, apiEndpoint, config, timeout)
+
+	if apiError, ok := err.(*APIError); ok && apiError.StatusCode == http.StatusUnauthorized && c.ReAuthenticate && len(c.Username) != 0 {
+		if c.refreshSession(timeout) != nil {
+			return nil, err
+		}
+
+		return c.retryRequest(callType, apiVersion, apiEndpoint, config, timeout)
+	}
 
-	requestURL := fmt.Sprintf("https://%s/api/%s%s", c.NodeIP, apiVersion, apiEndpoint)
+	return apiResponse, err
+}
+
+// retryRequest sends the API call and retries it, up to "MaxRetries" times, while it is rate limited or, for GET requests, fails
+// with a server error.
+func (c *Credentials) retryRequest(callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (interface{}, error) {
+
+	maxRetries := c.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 3
+	}
+
+	for attempt := 0; ; attempt++ {
+		apiResponse, err := c.sendRequest(callType, apiVersion, apiEndpoint, config, timeout)
+
+		apiError, ok := err.(*APIError)
+		if ok != true || attempt >= maxRetries || retryableStatus(callType, apiError.StatusCode) == false {
+			return apiResponse, err
+		}
+
+		delay := apiError.RetryAfter
+		if delay <= 0 {
+			delay = time.Duration(1<<uint(attempt)) * time.Second
+			if delay > 30*time.Second {
+				delay = 30 * time.Second
+			}
+		}
+
+		if c.logger != nil {
+			c.logger.Printf("%s /api/%s%s returned %d, retrying in %s", callType, apiVersion, apiEndpoint, apiError.StatusCode, delay)
+		}
+
+		time.Sleep(delay)
+	}
+}
+
+// retryableStatus determines if a request that failed with the "statusCode" should be retried. Only GET requests are retried after a
+// server error since other requests may have been processed by the Rubrik cluster before it failed.
+func retryableStatus(callType string, statusCode int) bool {
+
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return callType == "GET" && statusCode >= 500
+}
+
+// retryAfter returns the delay requested by the Retry-After header, which contains either a number of seconds or an HTTP date.
+func retryAfter(header http.Header) time.Duration {
+
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
+
+// refreshSession replaces the "APIToken" with a new session token created with the "Username" and "Password". The request is passed
+// to the "BeforeRequest" and "AfterResponse" hooks and recorded by the "Metrics" like any other API call. It is still sent during a
+// DryRun since creating a session token does not change the configuration of the Rubrik cluster and is needed for the GET requests
+// that continue to be sent.
+func (c *Credentials) refreshSession(timeout int) error {
+
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v1/session", c.baseURL()), bytes.NewBufferString("{}"))
+	if err != nil {
+		return err
+	}
+
+	request.SetBasicAuth(c.Username, c.Password)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	if c.BeforeRequest != nil {
+		c.BeforeRequest(request)
+	}
+
+	requestStart := time.Now()
+	response, err := c.httpClient(timeout).Do(request)
+	if err != nil {
+		c.metrics().RecordRequest("POST", metricsEndpoint("v1", "/session"), "error", time.Since(requestStart))
+		return err
+	}
+	defer response.Body.Close()
+
+	requestDuration := time.Since(requestStart)
+
+	c.metrics().RecordRequest("POST", metricsEndpoint("v1", "/session"), statusClass(response.StatusCode), requestDuration)
+
+	if c.AfterResponse != nil {
+		c.AfterResponse(response, requestDuration)
+	}
+
+	if response.StatusCode >= 400 {
+		return &APIError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+
+	var session struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&session); err != nil || len(session.Token) == 0 {
+		return errors.New("The Rubrik cluster did not return a session token.")
+	}
+
+	if c.logger != nil {
+		c.logger.Printf("Re-authenticated against the Rubrik cluster %s", c.NodeIP)
+	}
+
+	c.APIToken = session.Token
+
+	return nil
+}
+
+// RawRequest makes a single API call to the Rubrik cluster and returns the full HTTP response, including the status and headers,
+// along with the unprocessed response body. Unlike Get() and Post(), the body is not decoded and an error is not returned for a
+// 4xx or 5xx status, so RawRequest can be used with endpoints that return data in the response headers (ex: Location). The
+// response body has already been read and closed. During a DryRun, a synthetic 200 OK response is returned for any request other
+// than GET.
+//
+// Valid "method" choices are:
+//
+//	GET, POST, PATCH, PUT, DELETE
+func (c *Credentials) RawRequest(method, apiVersion, apiEndpoint string, body interface{}, timeout ...int) (*http.Response, []byte, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.doRequest(method, apiVersion, apiEndpoint, body, httpTimeout)
+}
+
+// sendRequest makes a single API call to the Rubrik cluster and returns the decoded API response.
+func (c *Credentials) sendRequest(callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (interface{}, error) {
+
+	apiRequest, apiResponse, err := c.doRequest(callType, apiVersion, apiEndpoint, config, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.DryRun && callType != "GET" {
+		return map[string]interface{}{"statusCode": http.StatusOK, "dryRun": true}, nil
+	}
+
+	convertedAPIResponse, err := c.decodeResponse(apiResponse)
+	if err != nil {
+
+		// DELETE request will return a 204 No Content status and other successful calls may also return an empty body
+		if apiRequest.StatusCode >= 200 && apiRequest.StatusCode <= 299 {
+			convertedAPIResponse = map[string]interface{}{}
+			convertedAPIResponse.(map[string]interface{})["statusCode"] = apiRequest.StatusCode
+			return convertedAPIResponse, nil
+		}
+
+		return nil, &APIError{StatusCode: apiRequest.StatusCode, Status: apiRequest.Status, RetryAfter: retryAfter(apiRequest.Header)}
+	}
+
+	if responseMap, ok := convertedAPIResponse.(map[string]interface{}); ok {
+		apiError := &APIError{StatusCode: apiRequest.StatusCode, Status: apiRequest.Status, RetryAfter: retryAfter(apiRequest.Header)}
+		if message, ok := responseMap["message"]; ok {
+			apiError.Message = fmt.Sprint(message)
+		}
+
+		if _, ok := responseMap["errorType"]; ok {
+			return nil, apiError
+		}
+
+		if _, ok := responseMap["message"]; ok {
+			// Add exception for bootstrap
+			if _, ok := responseMap["setupEncryptionAtRest"]; ok {
+				return convertedAPIResponse, nil
+			}
+
+			return nil, apiError
+		}
+	}
+
+	if apiRequest.StatusCode >= 400 {
+		return nil, &APIError{StatusCode: apiRequest.StatusCode, Status: apiRequest.Status, RetryAfter: retryAfter(apiRequest.Header)}
+	}
+
+	return convertedAPIResponse, nil
+
+}
+
+// doRequest sends a single API call to the Rubrik cluster and returns the HTTP response along with the response body.
+func (c *Credentials) doRequest(callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (*http.Response, []byte, error) {
+
+	if apiVersionValidation(apiVersion) == false {
+		return nil, nil, errors.New("Enter a valid API version.")
+	}
+
+	if endpointValidation(apiEndpoint) == "errorStart" {
+		return nil, nil, errors.New("The API Endpoint should begin with '/' (ex: /cluster/me).")
+	} else if endpointValidation(apiEndpoint) == "errorEnd" {
+		return nil, nil, errors.New("The API Endpoint should not end with '/' (ex. /cluster/me).")
+	}
+
+	client := c.httpClient(timeout)
+
+	requestURL := fmt.Sprintf("%s/api/%s%s", c.baseURL(), apiVersion, apiEndpoint)
 
 	var request *http.Request
+	var convertedConfig []byte
+	var err error
 	switch callType {
 	case "GET":
-		request, _ = http.NewRequest(callType, getEscape(requestURL), nil)
-	case "POST":
-		convertedConfig, _ := json.Marshal(config)
-		request, _ = http.NewRequest(callType, requestURL, bytes.NewBuffer(convertedConfig))
-	case "PATCH":
-		convertedConfig, _ := json.Marshal(config)
-		request, _ = http.NewRequest(callType, requestURL, bytes.NewBuffer(convertedConfig))
+		request, err = http.NewRequest(callType, escapeRequestURL(requestURL), nil)
+	case "POST", "PATCH", "PUT":
+		convertedConfig, err = json.Marshal(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		request, err = http.NewRequest(callType, requestURL, bytes.NewBuffer(convertedConfig))
 	case "DELETE":
-		request, _ = http.NewRequest(callType, requestURL, nil)
+		request, err = http.NewRequest(callType, requestURL, nil)
+	default:
+		return nil, nil, fmt.Errorf("'%s' is not a supported API call type.", callType)
 	}
-	if len(c.Username) != 0 {
-		request.SetBasicAuth(c.Username, c.Password)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	c.authorize(request)
+
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
 
+	if c.DryRun && callType != "GET" {
+		c.logDryRun(callType, request.URL.String(), convertedConfig)
+		return dryRunResponse(request), []byte(`{"statusCode": 200, "dryRun": true}`), nil
+	}
+
+	if c.logger != nil && len(convertedConfig) != 0 {
+		c.logger.Printf("%s %s request body: %s", callType, request.URL, redactRequestBody(convertedConfig))
+	}
+
+	if c.BeforeRequest != nil {
+		c.BeforeRequest(request)
+	}
+
+	requestStart := time.Now()
 	apiRequest, err := client.Do(request)
+	if err != nil {
+		c.metrics().RecordRequest(callType, metricsEndpoint(apiVersion, apiEndpoint), "error", time.Since(requestStart))
+		if c.logger != nil {
+			c.logger.Printf("%s %s failed after %s: %s", callType, request.URL, time.Since(requestStart), err)
+		}
+	}
 	if err, ok := err.(net.Error); ok && err.Timeout() {
-		log.Fatalf("Error: Unable to establish a connection to the Rubrik cluster.")
+		return nil, nil, errors.New("Unable to establish a connection to the Rubrik cluster.")
 	} else if err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
+	defer apiRequest.Body.Close()
 
-	body, err := ioutil.ReadAll(apiRequest.Body)
-
-	apiResponse := []byte(body)
+	requestDuration := time.Since(requestStart)
 
-	var convertedAPIResponse interface{}
+	c.metrics().RecordRequest(callType, metricsEndpoint(apiVersion, apiEndpoint), statusClass(apiRequest.StatusCode), requestDuration)
 
-	if err := json.Unmarshal(apiResponse, &convertedAPIResponse); err != nil {
+	if c.logger != nil {
+		c.logger.Printf("%s %s %d (%s)", callType, request.URL, apiRequest.StatusCode, requestDuration)
+	}
 
-		// DELETE request will return a 204 No Content status
-		if apiRequest.StatusCode == 204 {
-			convertedAPIResponse = map[string]interface{}{}
-			convertedAPIResponse.(map[string]interface{})["statusCode"] = apiRequest.StatusCode
-		} else if apiRequest.StatusCode != 200 {
-			log.Fatalf("Error: %s", apiRequest.Status)
-		}
+	if c.AfterResponse != nil {
+		c.AfterResponse(apiRequest, requestDuration)
+	}
 
+	apiResponse, err := ioutil.ReadAll(apiRequest.Body)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if _, ok := convertedAPIResponse.(map[string]interface{})["errorType"]; ok {
-		fmt.Println("1")
-		fmt.Println(convertedAPIResponse)
-		log.Fatalf("Error: %s", convertedAPIResponse.(map[string]interface{})["message"])
+	return apiRequest, apiResponse, nil
+}
+
+// dryRunResponse returns the synthetic response used in place of a request that was not sent because "DryRun" is true.
+func dryRunResponse(request *http.Request) *http.Response {
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    request,
 	}
+}
 
-	if _, ok := convertedAPIResponse.(map[string]interface{})["message"]; ok {
-		// Add exception for bootstrap
-		if _, ok := convertedAPIResponse.(map[string]interface{})["setupEncryptionAtRest"]; ok {
-			return convertedAPIResponse
+// decodeResponse decodes the JSON "body" of an API response, preserving the precision of numbers when "PreserveNumbers" is true.
+func (c *Credentials) decodeResponse(body []byte) (interface{}, error) {
 
-		}
+	var apiResponse interface{}
+	if c.PreserveNumbers == false {
+		err := json.Unmarshal(body, &apiResponse)
+		return apiResponse, err
+	}
 
-		log.Fatalf("Error: %s", convertedAPIResponse.(map[string]interface{})["message"])
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&apiResponse); err != nil {
+		return nil, err
 	}
 
-	return convertedAPIResponse
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("The API response contains data after the JSON value.")
+	}
 
+	return apiResponse, nil
 }
 
 // apiVersionValidation validates the API Version provided in the Base API functions. Valid versions are v1, v2 and internal.
@@ -203,20 +679,48 @@ func endpointValidation(apiEndpoint string) string {
 	return "succes"
 }
 
-// httpTimeout returns a default timeout value of 15 or use the value provided by the end user
-func httpTimeout(timeout []int) int {
-	if len(timeout) == 0 {
-		return int(15) // if not timeout value is provided, set the default to 15
+// httpTimeout returns the timeout value provided by the end user, the Credentials DefaultTimeout, or a default of 15
+func (c *Credentials) httpTimeout(timeout []int) int {
+	if len(timeout) != 0 {
+		return int(timeout[0]) // set the timeout value to the first value in the timeout slice
+	}
+	if c.DefaultTimeout > 0 {
+		return c.DefaultTimeout
 	}
-	return int(timeout[0]) // set the timeout value to the first value in the timeout slice
+	return int(15) // if not timeout value is provided, set the default to 15
 
 }
 
+// longHTTPTimeout is used in place of httpTimeout for API calls that take longer to complete, such as snapshots, and defaults to 180
+// seconds when neither a per-call timeout nor the Credentials DefaultTimeout is provided.
+func (c *Credentials) longHTTPTimeout(timeout []int) int {
+	if len(timeout) == 0 && c.DefaultTimeout <= 0 {
+		return int(180)
+	}
+	return c.httpTimeout(timeout)
+}
+
 // getEscape is a custom implementation of url.PathEscape.
 func getEscape(s string) string {
 	return escape(s, encodePathSegment)
 }
 
+// queryEndpoint appends the URL encoded "query" parameters to the "apiEndpoint" so that values containing reserved characters, such
+// as an object name with an '&' or spaces, are sent to the Rubrik cluster as a single query parameter.
+func queryEndpoint(apiEndpoint string, query url.Values) string {
+	return fmt.Sprintf("%s?%s", apiEndpoint, query.Encode())
+}
+
+// escapeRequestURL escapes the path of the "requestURL". Any query parameters are left unchanged since they are expected to have
+// already been encoded, such as by queryEndpoint(), and escaping them again would change their values.
+func escapeRequestURL(requestURL string) string {
+	if i := strings.Index(requestURL, "?"); i != -1 {
+		return getEscape(requestURL[:i]) + requestURL[i:]
+	}
+
+	return getEscape(requestURL)
+}
+
 func escape(s string, mode encoding) string {
 	spaceCount, hexCount := 0, 0
 	for i := 0; i < len(s); i++ {
@@ -278,12 +782,18 @@ func shouldEscape(c byte, mode encoding) bool {
 
 // Get sends a GET request to the provided Rubrik API endpoint and returns the full API response. Supported "apiVersions" are v1, v2, and internal.
 // The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
-// timeout error. If no value is provided, a default of 15 seconds will be used.
+// timeout error. If no value is provided, a default of 15 seconds will be used. Any query parameters in the "apiEndpoint" must already
+// be URL encoded (ex: with url.Values.Encode()).
 func (c *Credentials) Get(apiVersion, apiEndpoint string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
 
-	return c.commonAPI("GET", apiVersion, apiEndpoint, nil, httpTimeout)
+	return apiRequest
 
 }
 
@@ -292,9 +802,14 @@ func (c *Credentials) Get(apiVersion, apiEndpoint string, timeout ...int) interf
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Post(apiVersion, apiEndpoint string, config interface{}, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("POST", apiVersion, apiEndpoint, config, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
 
-	return c.commonAPI("POST", apiVersion, apiEndpoint, config, httpTimeout)
+	return apiRequest
 }
 
 // Patch sends a PATCH request to the provided Rubrik API endpoint and returns the full API response. Supported "apiVersions" are v1, v2, and internal.
@@ -302,9 +817,29 @@ func (c *Credentials) Post(apiVersion, apiEndpoint string, config interface{}, t
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Patch(apiVersion, apiEndpoint string, config interface{}, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	return c.commonAPI("PATCH", apiVersion, apiEndpoint, config, httpTimeout)
+	apiRequest, err := c.commonAPI("PATCH", apiVersion, apiEndpoint, config, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return apiRequest
+}
+
+// Put sends a PUT request to the provided Rubrik API endpoint and returns the full API response. Supported "apiVersions" are v1, v2, and internal.
+// The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
+// timeout error. If no value is provided, a default of 15 seconds will be used.
+func (c *Credentials) Put(apiVersion, apiEndpoint string, config interface{}, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("PUT", apiVersion, apiEndpoint, config, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return apiRequest
 }
 
 // Delete sends a DELETE request to the provided Rubrik API endpoint and returns the full API response. Supported "apiVersions" are v1, v2, and internal.
@@ -312,9 +847,321 @@ func (c *Credentials) Patch(apiVersion, apiEndpoint string, config interface{},
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Delete(apiVersion, apiEndpoint string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("DELETE", apiVersion, apiEndpoint, nil, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return apiRequest
+}
+
+// convertResponse converts a decoded API response into the typed value pointed to by v.
+func convertResponse(apiResponse interface{}, v interface{}) error {
+
+	convertedResponse, err := json.Marshal(apiResponse)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(convertedResponse, v)
+}
+
+// WaitForJob polls the asynchronous job located at "jobStatusURL", such as the URL returned by OnDemandSnapshotVM(), until the job
+// completes. An error is returned if the job fails, is canceled, or does not complete within the "JobMaxWait" of the Credentials.
+// Any "timeout" applies to each individual status request.
+//
+// The function will return the full API response for the final job status.
+func (c *Credentials) WaitForJob(jobStatusURL string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	// The job status URL contains the full path to the job, (ex: https://{nodeIP}/api/v1/vmware/vm/request/{jobID})
+	jobPath := strings.SplitN(jobStatusURL, "/api/", 2)
+	if len(jobPath) != 2 {
+		return nil, fmt.Errorf("'%s' is not a valid job status URL.", jobStatusURL)
+	}
+
+	apiPath := strings.SplitN(jobPath[1], "/", 2)
+	if len(apiPath) != 2 {
+		return nil, fmt.Errorf("'%s' is not a valid job status URL.", jobStatusURL)
+	}
+
+	pollInterval := c.JobPollInterval
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	maxWait := c.JobMaxWait
+	if maxWait <= 0 {
+		maxWait = 2 * time.Hour
+	}
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		jobStatus, err := c.commonAPI("GET", apiPath[0], fmt.Sprintf("/%s", apiPath[1]), nil, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := getString(jobStatus, "status")
+		if err != nil {
+			return nil, err
+		}
+
+		switch status {
+		case "SUCCEEDED":
+			return jobStatus, nil
+		case "FAILED", "CANCELED":
+			if jobError, err := getString(jobStatus, "error", "message"); err == nil {
+				return nil, fmt.Errorf("The job finished with a %s status: %s", status, jobError)
+			}
+			return nil, fmt.Errorf("The job finished with a %s status.", status)
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Timed out waiting for the job '%s' to complete. The last job status is '%s'.", jobStatusURL, status)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// jobResultID returns the ID of the object created by a completed job, which is the final segment of the "result" link.
+func jobResultID(jobStatus interface{}) (string, error) {
+
+	links, err := getSlice(jobStatus, "links")
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range links {
+		rel, _ := getString(v, "rel")
+		if rel != "result" {
+			continue
+		}
+
+		href, err := getString(v, "href")
+		if err != nil {
+			return "", err
+		}
+
+		return href[strings.LastIndex(href, "/")+1:], nil
+	}
+
+	return "", errors.New("The job status does not contain a result link.")
+}
+
+// apiValue walks the nested maps of an API response following "path" and returns the value found at the end of the path.
+func apiValue(apiResponse interface{}, path []string) (interface{}, error) {
+
+	value := apiResponse
+	for i, key := range path {
+		object, ok := value.(map[string]interface{})
+		if ok != true {
+			return nil, fmt.Errorf("The API response does not contain the '%s' value.", strings.Join(path[:i+1], "."))
+		}
+
+		value, ok = object[key]
+		if ok != true {
+			return nil, fmt.Errorf("The API response does not contain the '%s' value.", strings.Join(path[:i+1], "."))
+		}
+	}
+
+	return value, nil
+}
+
+// getMap returns the map found at "path" in the API response, or the API response itself if no "path" is provided.
+func getMap(apiResponse interface{}, path ...string) (map[string]interface{}, error) {
+
+	value, err := apiValue(apiResponse, path)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := value.(map[string]interface{})
+	if ok != true {
+		return nil, fmt.Errorf("The API response value '%s' is not an object.", strings.Join(path, "."))
+	}
+
+	return object, nil
+}
+
+// getSlice returns the slice found at "path" in the API response, or the API response itself if no "path" is provided.
+func getSlice(apiResponse interface{}, path ...string) ([]interface{}, error) {
+
+	value, err := apiValue(apiResponse, path)
+	if err != nil {
+		return nil, err
+	}
+
+	slice, ok := value.([]interface{})
+	if ok != true {
+		return nil, fmt.Errorf("The API response value '%s' is not a list.", strings.Join(path, "."))
+	}
+
+	return slice, nil
+}
+
+// getString returns the string found at "path" in the API response, or the API response itself if no "path" is provided.
+func getString(apiResponse interface{}, path ...string) (string, error) {
+
+	value, err := apiValue(apiResponse, path)
+	if err != nil {
+		return "", err
+	}
+
+	str, ok := value.(string)
+	if ok != true {
+		return "", fmt.Errorf("The API response value '%s' is not a string.", strings.Join(path, "."))
+	}
+
+	return str, nil
+}
+
+// paginatedData returns the "data" of every page of a paginated API endpoint. The "limit" and "offset" query parameters are added to
+// "apiEndpoint" and pages are requested until the API response no longer reports "hasMore".
+func (c *Credentials) paginatedData(apiVersion, apiEndpoint string, timeout int) ([]interface{}, error) {
+
+	separator := "?"
+	if strings.Contains(apiEndpoint, "?") {
+		separator = "&"
+	}
+
+	data := []interface{}{}
+	for {
+		page, err := c.commonAPI("GET", apiVersion, fmt.Sprintf("%s%slimit=%d&offset=%d", apiEndpoint, separator, pageSize, len(data)), nil, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		pageData, err := getSlice(page, "data")
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, pageData...)
+
+		if hasMore, _ := apiValue(page, []string{"hasMore"}); hasMore != true || len(pageData) == 0 {
+			return data, nil
+		}
+	}
+}
+
+// Int64Value converts a number from an API response, decoded as either a float64 or a json.Number, to an int64.
+func Int64Value(value interface{}) (int64, error) {
+
+	switch number := value.(type) {
+	case json.Number:
+		if i, err := number.Int64(); err == nil {
+			return i, nil
+		}
+
+		f, err := number.Float64()
+		if err != nil || f != math.Trunc(f) {
+			return 0, fmt.Errorf("The value '%s' is not an integer.", number)
+		}
+		return int64(f), nil
+	case float64:
+		if number != math.Trunc(number) {
+			return 0, fmt.Errorf("The value '%v' is not an integer.", number)
+		}
+		return int64(number), nil
+	case int:
+		return int64(number), nil
+	case int64:
+		return number, nil
+	}
+
+	return 0, fmt.Errorf("The value '%v' is not a number.", value)
+}
+
+// Float64Value converts a number from an API response, decoded as either a float64 or a json.Number, to a float64.
+func Float64Value(value interface{}) (float64, error) {
+
+	switch number := value.(type) {
+	case json.Number:
+		return number.Float64()
+	case float64:
+		return number, nil
+	case int:
+		return float64(number), nil
+	case int64:
+		return float64(number), nil
+	}
+
+	return 0, fmt.Errorf("The value '%v' is not a number.", value)
+}
+
+// numberEq determines if the number "value" from an API response is equal to "expected".
+func numberEq(value interface{}, expected int) bool {
+
+	number, err := Int64Value(value)
+
+	return err == nil && number == int64(expected)
+}
+
+// jobStatusURL returns the job status URL (links[0].href) from the API response of an asynchronous request.
+func jobStatusURL(apiResponse interface{}) (string, error) {
+
+	asyncRequest, ok := apiResponse.(map[string]interface{})
+	if ok != true {
+		return "", errors.New("The API response does not contain a job status URL.")
+	}
+
+	links, ok := asyncRequest["links"].([]interface{})
+	if ok != true || len(links) == 0 {
+		return "", errors.New("The API response does not contain a job status URL.")
+	}
+
+	link, ok := links[0].(map[string]interface{})
+	if ok != true {
+		return "", errors.New("The API response does not contain a job status URL.")
+	}
+
+	href, ok := link["href"].(string)
+	if ok != true {
+		return "", errors.New("The API response does not contain a job status URL.")
+	}
+
+	return href, nil
+}
+
+// download saves the file located at "fileURL" to "filePath". "fileURL" may either be a full URL or a path on the Rubrik cluster.
+func (c *Credentials) download(fileURL, filePath string, timeout int) error {
+
+	if strings.HasPrefix(fileURL, "/") {
+		fileURL = fmt.Sprintf("%s%s", c.baseURL(), fileURL)
+	}
+
+	request, err := http.NewRequest("GET", fileURL, nil)
+	if err != nil {
+		return err
+	}
+
+	c.authorize(request)
+
+	response, err := c.httpClient(timeout).Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		return &APIError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, response.Body); err != nil {
+		file.Close()
+		return err
+	}
 
-	return c.commonAPI("DELETE", apiVersion, apiEndpoint, nil, httpTimeout)
+	return file.Close()
 }
 
 // stringEq converts b to []string, sorts the two []string, and checks for equality
@@ -0,0 +1,1651 @@
+package rubrikcdm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClusterFailure starts a TLS server that responds to each API endpoint (ex: /api/v1/cluster/me) with the matching JSON
+// body and returns Credentials that send their requests to it. An endpoint prefixed with a method (ex: PATCH /api/v1/cluster/me)
+// only matches requests sent with that method. Each of the "failures" (ex: PATCH /api/v1/cluster/me) receives a 400 Rubrik API
+// error. The Credentials authenticate with an API token so the cluster summary is not requested when connecting.
+func testClusterFailure(t *testing.T, responses map[string]string, failures ...string) *Credentials {
+	t.Helper()
+
+	server := testServer(t, responses, failures)
+
+	return ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+}
+
+func testServer(t *testing.T, responses map[string]string, failures []string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+		for _, failure := range failures {
+			if failure == request {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errorType": "user_error", "message": "The request was rejected"}`))
+				return
+			}
+		}
+
+		body, ok := responses[request]
+		if ok != true {
+			body, ok = responses[r.URL.Path]
+		}
+		if ok != true {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// assertAPIError fails the test unless "err" is the 400 APIError returned by testClusterFailure.
+func assertAPIError(t *testing.T, function string, err error) {
+	t.Helper()
+
+	if apiError, ok := err.(*APIError); ok != true || apiError.StatusCode != http.StatusBadRequest {
+		t.Errorf("%s returned %v; want a 400 APIError", function, err)
+	}
+}
+
+// testCluster starts a TLS server that responds to each API endpoint (ex: /api/v1/cluster/me) with the matching JSON body and
+// returns Credentials that send their requests to it.
+func testCluster(t *testing.T, responses map[string]string) *Credentials {
+	t.Helper()
+
+	if _, ok := responses["/api/v1/cluster/me"]; ok != true {
+		responses["/api/v1/cluster/me"] = `{"id": "cluster-1", "version": "5.0.0"}`
+	}
+
+	server := testServer(t, responses, nil)
+
+	rubrik, err := Connect(strings.TrimPrefix(server.URL, "https://"), "admin", "password")
+	if err != nil {
+		t.Fatalf("Connect() returned an unexpected error: %s", err)
+	}
+	return rubrik
+}
+
+func decodeJSON(t *testing.T, body string) interface{} {
+	t.Helper()
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		t.Fatalf("invalid test JSON %q: %s", body, err)
+	}
+	return value
+}
+
+func TestGetClusterName(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/v1/cluster/me": `{"id": "cluster-1", "name": "rubrik01"}`,
+	})
+
+	name, err := rubrik.GetClusterName()
+	if err != nil || name != "rubrik01" {
+		t.Errorf("GetClusterName() = %q, %v; want \"rubrik01\", nil", name, err)
+	}
+
+	rubrik = testClusterFailure(t, map[string]string{}, "GET /api/v1/cluster/me")
+
+	_, err = rubrik.GetClusterName()
+	assertAPIError(t, "GetClusterName()", err)
+}
+
+func TestSetClusterName(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/v1/cluster/me": `{"id": "cluster-1", "name": "rubrik01"}`,
+	}, "PATCH /api/v1/cluster/me")
+
+	setName, err := rubrik.SetClusterName("rubrik01")
+	if err != nil || setName != "No change required. The Rubrik cluster is already named 'rubrik01'." {
+		t.Errorf("SetClusterName() = %v, %v; want the no change message", setName, err)
+	}
+
+	_, err = rubrik.SetClusterName("rubrik02")
+	assertAPIError(t, "SetClusterName()", err)
+}
+
+func TestClusterStorage(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/stats/system_storage": `{"total": 1000, "used": 400, "available": 600, "snapshot": 300, "liveMount": 50, "miscellaneous": 50}`,
+	})
+
+	storage, err := rubrik.ClusterStorage()
+	if err != nil || storage.Total != 1000 || storage.Available != 600 {
+		t.Errorf("ClusterStorage() = %+v, %v", storage, err)
+	}
+
+	rubrik = testClusterFailure(t, map[string]string{}, "GET /api/internal/stats/system_storage")
+
+	_, err = rubrik.ClusterStorage()
+	assertAPIError(t, "ClusterStorage()", err)
+}
+
+func TestClusterRunway(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/stats/runway_remaining": `{"days": 180}`,
+	})
+
+	days, _, err := rubrik.ClusterRunway()
+	if err != nil || days != 180 {
+		t.Errorf("ClusterRunway() = %d, %v; want 180, nil", days, err)
+	}
+
+	rubrik = testClusterFailure(t, map[string]string{}, "GET /api/internal/stats/runway_remaining")
+
+	_, _, err = rubrik.ClusterRunway()
+	assertAPIError(t, "ClusterRunway()", err)
+}
+
+func TestSupportTunnel(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/node_management/support_tunnel": `{"isTunnelEnabled": false, "port": 0}`,
+	}, "PATCH /api/internal/node_management/support_tunnel")
+
+	closeTunnel, err := rubrik.CloseSupportTunnel()
+	if err != nil || closeTunnel != "No change required. The support tunnel is already closed." {
+		t.Errorf("CloseSupportTunnel() = %v, %v; want the no change message", closeTunnel, err)
+	}
+
+	_, err = rubrik.OpenSupportTunnel(3600)
+	assertAPIError(t, "OpenSupportTunnel()", err)
+
+	rubrik = testClusterFailure(t, map[string]string{}, "GET /api/internal/node_management/support_tunnel")
+
+	_, err = rubrik.SupportTunnelStatus()
+	assertAPIError(t, "SupportTunnelStatus()", err)
+}
+
+func TestAddVCenter(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/vmware/vcenter": `{"total": 1, "data": [{"hostname": "vcsa.gosdk.lab", "id": "vCenter:::1"}]}`,
+	}
+
+	rubrik := testClusterFailure(t, responses, "POST /api/v1/vmware/vcenter")
+
+	addVCenter, err := rubrik.AddVCenter("vcsa.gosdk.lab", "administrator@vsphere.local", "password")
+	if err != nil || addVCenter != "No change required. The vCenter 'vcsa.gosdk.lab' has already been added to the Rubrik cluster." {
+		t.Errorf("AddVCenter() = %v, %v; want the no change message", addVCenter, err)
+	}
+
+	_, err = rubrik.AddVCenter("vcsa02.gosdk.lab", "administrator@vsphere.local", "password", "-----BEGIN CERTIFICATE-----")
+	assertAPIError(t, "AddVCenter()", err)
+}
+
+func TestRefreshvCenter(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/vmware/vcenter": `{"total": 1, "data": [{"name": "vcsa.gosdk.lab", "id": "vCenter:::1"}]}`,
+	}
+
+	rubrik := testClusterFailure(t, responses, "POST /api/v1/vmware/vcenter/vCenter:::1/refresh")
+
+	_, err := rubrik.RefreshvCenter("vcsa.gosdk.lab")
+	assertAPIError(t, "RefreshvCenter()", err)
+
+	responses["/api/v1/vmware/vcenter/vCenter:::1/refresh"] = `{"id": "job-1", "links": [{"href": "https://rubrik/api/v1/vmware/vcenter/request/job-1"}]}`
+	rubrik = testClusterFailure(t, responses)
+
+	jobURL, err := rubrik.RefreshvCenter("vcsa.gosdk.lab")
+	if err != nil || strings.HasSuffix(jobURL, "job-1") == false {
+		t.Errorf("RefreshvCenter() = %q, %v", jobURL, err)
+	}
+}
+
+func TestVMwareGuestCredentials(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/vmware/guest_credential": `{"total": 1, "data": [{"id": "credential-1", "username": "backup", "domain": "corp.local"}]}`,
+	}, "PUT /api/internal/vmware/guest_credential/credential-1", "POST /api/internal/vmware/guest_credential", "DELETE /api/internal/vmware/guest_credential/credential-1")
+
+	_, err := rubrik.SetVMwareGuestCredential("backup", "password", "corp.local")
+	assertAPIError(t, "SetVMwareGuestCredential() for an existing credential", err)
+
+	_, err = rubrik.SetVMwareGuestCredential("backup", "password", "")
+	assertAPIError(t, "SetVMwareGuestCredential() for a new credential", err)
+
+	_, err = rubrik.DeleteVMwareGuestCredential("backup", "corp.local")
+	assertAPIError(t, "DeleteVMwareGuestCredential()", err)
+
+	deleteCredential, err := rubrik.DeleteVMwareGuestCredential("backup", "")
+	if err != nil || deleteCredential != "No change required. The guest credential 'backup' is not present on the Rubrik cluster." {
+		t.Errorf("DeleteVMwareGuestCredential() = %v, %v; want the no change message", deleteCredential, err)
+	}
+
+	rubrik = testClusterFailure(t, map[string]string{}, "GET /api/internal/vmware/guest_credential")
+
+	_, err = rubrik.GetVMwareGuestCredentials()
+	assertAPIError(t, "GetVMwareGuestCredentials()", err)
+}
+
+func TestExcludeVMDisksPatchError(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/v1/vmware/vm":                              `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+		"/api/v1/vmware/vm/VirtualMachine:::1":           `{"id": "VirtualMachine:::1", "virtualDiskIds": ["VirtualDisk:::1", "VirtualDisk:::2"]}`,
+		"/api/v1/vmware/vm/virtual_disk/VirtualDisk:::1": `{"id": "VirtualDisk:::1", "deviceKey": 2000, "excludeFromSnapshots": true}`,
+		"/api/v1/vmware/vm/virtual_disk/VirtualDisk:::2": `{"id": "VirtualDisk:::2", "deviceKey": 2001, "excludeFromSnapshots": false}`,
+	}, "PATCH /api/v1/vmware/vm/virtual_disk/VirtualDisk:::2")
+
+	excludeDisks, err := rubrik.ExcludeVMDisks("vm01", []int{2000})
+	if err != nil || excludeDisks != "No change required. The provided virtual disks are already excluded from snapshots of the 'vm01' VM." {
+		t.Errorf("ExcludeVMDisks() = %v, %v; want the no change message", excludeDisks, err)
+	}
+
+	excludeDisks, err = rubrik.ExcludeVMDisks("vm01", []int{2000, 2001})
+	if excludeDisks != nil {
+		t.Errorf("ExcludeVMDisks() returned %v after a virtual disk failed to update", excludeDisks)
+	}
+	assertAPIError(t, "ExcludeVMDisks()", err)
+
+	if _, err := rubrik.IncludeVMDisks("vm01", []int{2002}); err == nil {
+		t.Error("expected an error for a device key the VM does not have")
+	}
+}
+
+func TestSetVMwareVMFlags(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/v1/vmware/vm":                    `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+		"/api/v1/vmware/vm/VirtualMachine:::1": `{"id": "VirtualMachine:::1", "isArrayIntegrationEnabled": true, "isCbtEnabled": true}`,
+	}, "PATCH /api/v1/vmware/vm/VirtualMachine:::1")
+
+	arrayIntegration, err := rubrik.SetVMwareArrayIntegration("vm01", true)
+	if err != nil || arrayIntegration != "No change required. Array integration is already set to 'true' for the 'vm01' VM." {
+		t.Errorf("SetVMwareArrayIntegration() = %v, %v; want the no change message", arrayIntegration, err)
+	}
+
+	_, err = rubrik.SetVMwareCBT("vm01", false)
+	assertAPIError(t, "SetVMwareCBT()", err)
+
+	rubrik = testClusterFailure(t, map[string]string{
+		"/api/v1/vmware/vm": `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+	}, "GET /api/v1/vmware/vm/VirtualMachine:::1")
+
+	_, err = rubrik.SetVMwareCBT("vm01", true)
+	assertAPIError(t, "SetVMwareCBT()", err)
+}
+
+func TestCreateManagedVolume(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/managed_volume": `{"total": 1, "data": [{"name": "mv01", "id": "ManagedVolume:::1"}]}`,
+	}, "POST /api/internal/managed_volume", "DELETE /api/internal/managed_volume/ManagedVolume:::1")
+
+	createVolume, err := rubrik.CreateManagedVolume("mv01", 1073741824, 1, nil)
+	if err != nil || createVolume != "No change required. The Managed Volume 'mv01' already exists on the Rubrik cluster." {
+		t.Errorf("CreateManagedVolume() = %v, %v; want the no change message", createVolume, err)
+	}
+
+	_, err = rubrik.CreateManagedVolume("mv02", 1073741824, 1, nil)
+	assertAPIError(t, "CreateManagedVolume()", err)
+
+	_, err = rubrik.DeleteManagedVolume("mv01")
+	assertAPIError(t, "DeleteManagedVolume()", err)
+
+	rubrik = testClusterFailure(t, map[string]string{}, "GET /api/internal/managed_volume")
+
+	_, err = rubrik.CreateManagedVolume("mv01", 1073741824, 1, nil)
+	assertAPIError(t, "CreateManagedVolume()", err)
+}
+
+func TestManagedVolumeExport(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/cluster/me":                                      `{"id": "cluster-1", "timezone": {"timezone": "UTC"}}`,
+		"/api/internal/managed_volume":                            `{"total": 1, "data": [{"name": "mv01", "id": "ManagedVolume:::1"}]}`,
+		"/api/internal/managed_volume/ManagedVolume:::1/snapshot": `{"total": 1, "data": [{"id": "snapshot-1", "date": "2026-10-01T15:30:00Z"}]}`,
+	}
+
+	rubrik := testClusterFailure(t, responses, "POST /api/internal/managed_volume/snapshot/snapshot-1/export")
+
+	_, err := rubrik.ManagedVolumeExport("mv01", "10-01-2026", "03:30 PM", []string{"10.0.0.5"})
+	assertAPIError(t, "ManagedVolumeExport()", err)
+
+	rubrik = testClusterFailure(t, responses, "GET /api/internal/managed_volume/ManagedVolume:::1/snapshot")
+
+	_, err = rubrik.ManagedVolumeExport("mv01", "10-01-2026", "03:30 PM", []string{"10.0.0.5"})
+	assertAPIError(t, "ManagedVolumeExport() snapshot lookup", err)
+
+	responses["POST /api/internal/managed_volume/snapshot/snapshot-1/export"] = `{"id": "export-1", "links": []}`
+	rubrik = testClusterFailure(t, responses)
+
+	if export, err := rubrik.ManagedVolumeExport("mv01", "10-01-2026", "03:30 PM", []string{"10.0.0.5"}); err == nil {
+		t.Errorf("expected an error when the export response does not contain a job status URL, got %+v", export)
+	}
+
+	responses["POST /api/internal/managed_volume/snapshot/snapshot-1/export"] = `{"id": "export-1", "links": [{"href": "https://rubrik/api/internal/managed_volume/request/job-1"}]}`
+	rubrik = testClusterFailure(t, responses)
+
+	export, err := rubrik.ManagedVolumeExport("mv01", "10-01-2026", "03:30 PM", []string{"10.0.0.5"})
+	if err != nil || export.SnapshotID != "snapshot-1" || strings.HasSuffix(export.JobStatusURL, "job-1") == false {
+		t.Errorf("ManagedVolumeExport() = %+v, %v", export, err)
+	}
+}
+
+func TestGetManagedVolumeExports(t *testing.T) {
+	responses := map[string]string{
+		"/api/internal/managed_volume/snapshot/export":     `{"total": 1, "data": [{"id": "export-1", "snapshotId": "snapshot-1", "sourceManagedVolumeId": "ManagedVolume:::1", "sourceManagedVolumeName": "mv01"}]}`,
+		"/api/internal/managed_volume/snapshot/snapshot-1": `{"id": "snapshot-1", "date": "2026-10-01T15:30:00Z"}`,
+	}
+
+	rubrik := testClusterFailure(t, responses, "DELETE /api/internal/managed_volume/snapshot/export/export-1")
+
+	exports, err := rubrik.GetManagedVolumeExports()
+	if err != nil || len(exports) != 1 || exports[0].ManagedVolumeName != "mv01" || exports[0].SnapshotDate.Day() != 1 {
+		t.Errorf("GetManagedVolumeExports() = %+v, %v", exports, err)
+	}
+
+	_, err = rubrik.DeleteManagedVolumeExport("export-1")
+	assertAPIError(t, "DeleteManagedVolumeExport()", err)
+
+	rubrik = testClusterFailure(t, responses, "GET /api/internal/managed_volume/snapshot/snapshot-1")
+
+	exports, err = rubrik.GetManagedVolumeExports()
+	if exports != nil {
+		t.Errorf("GetManagedVolumeExports() returned %+v after a snapshot lookup failed", exports)
+	}
+	assertAPIError(t, "GetManagedVolumeExports()", err)
+}
+
+func TestAddAWSS3ArchivalLocation(t *testing.T) {
+	responses := map[string]string{
+		"/api/internal/archive/object_store": `{"total": 1, "data": [{"id": "archive-1", "definition": {"objectStoreType": "S3", "name": "AWS:S3:GoSDK", "bucket": "rubrikgosdk", "defaultRegion": "us-east-1", "storageClass": "STANDARD_IA", "accessKey": "access-key"}}]}`,
+	}
+
+	rubrik := testClusterFailure(t, responses, "POST /api/internal/archive/object_store")
+
+	addArchive, err := rubrik.AddAWSS3ArchivalLocation("AWS:S3:GoSDK", "rubrikgosdk", "us-east-1", "access-key", "secret-key", "standard_ia", "rsa-key")
+	if err != nil || addArchive != "No change required. The 'AWS:S3:GoSDK' archive location is already configured on the Rubrik cluster." {
+		t.Errorf("AddAWSS3ArchivalLocation() = %v, %v; want the no change message", addArchive, err)
+	}
+
+	if _, err = rubrik.AddAWSS3ArchivalLocation("AWS:S3:GoSDK", "rubrikgosdk", "us-east-1", "access-key", "secret-key", "STANDARD", "rsa-key"); err == nil {
+		t.Error("expected an error for an archive location with the same name and a different definition")
+	}
+
+	if _, err = rubrik.AddAWSS3ArchivalLocation("AWS:S3:GoSDK02", "rubrikgosdk", "us-east-1", "access-key", "secret-key", "glacier", "rsa-key"); err == nil {
+		t.Error("expected an error for an unsupported storageClass")
+	}
+
+	_, err = rubrik.AddAWSS3ArchivalLocation("AWS:S3:GoSDK02", "rubrikgosdk02", "us-east-1", "access-key", "secret-key", "ONEZONE_IA", "rsa-key")
+	assertAPIError(t, "AddAWSS3ArchivalLocation()", err)
+}
+
+func TestAddAzureArchivalLocation(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/archive/object_store": `{"total": 1, "data": [{"id": "ArchivalLocation:::1", "definition": {"objectStoreType": "Azure", "name": "Azure:gosdk", "accessKey": "rubrikgosdk", "bucket": "gosdk", "isConsolidationEnabled": false, "proxySettings": {"protocol": "HTTP", "proxyServer": "proxy.gosdk.lab", "portNumber": 3128, "userName": "go"}}}]}`,
+	}, "POST /api/internal/archive/object_store")
+	rubrik.PreserveNumbers = true
+
+	options := AzureArchivalLocationOptions{RSAKey: "key", ProxyServer: "proxy.gosdk.lab", ProxyProtocol: "HTTP", ProxyPort: 3128, ProxyUsername: "go", ProxyPassword: "sdk"}
+
+	azureArchive, err := rubrik.AddAzureArchivalLocation("Azure:gosdk", "gosdk", "rubrikgosdk", "secret", "default", options)
+	if err != nil || azureArchive != "No change required. The 'Azure:gosdk' archive location is already configured on the Rubrik cluster." {
+		t.Errorf("AddAzureArchivalLocation() = %v, %v; want the no change message", azureArchive, err)
+	}
+
+	options.ProxyPort = 8080
+	if _, err := rubrik.AddAzureArchivalLocation("Azure:gosdk", "gosdk", "rubrikgosdk", "secret", "default", options); err == nil || strings.Contains(err.Error(), "already exists") == false {
+		t.Errorf("AddAzureArchivalLocation() returned %v; want an error for the existing archive location name", err)
+	}
+
+	_, err = rubrik.AddAzureArchivalLocation("Azure:gosdk02", "gosdk", "rubrikgosdk", "secret", "government", options)
+	assertAPIError(t, "AddAzureArchivalLocation()", err)
+
+	if _, err := rubrik.AddAzureArchivalLocation("Azure:gosdk02", "gosdk", "rubrikgosdk", "secret", "westus", options); err == nil {
+		t.Error("expected an error for an invalid Azure region")
+	}
+
+	options.ProxyProtocol = "FTP"
+	if _, err := rubrik.AddAzureArchivalLocation("Azure:gosdk02", "gosdk", "rubrikgosdk", "secret", "default", options); err == nil {
+		t.Error("expected an error for an invalid proxy protocol")
+	}
+}
+
+func TestAddNFSArchivalLocation(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/internal/archive/location": `{"total": 1, "data": [{"id": "ArchivalLocation:::1", "name": "NFS:GoSDK"}]}`,
+	}, "POST /api/internal/archive/nfs")
+
+	nfsArchive, err := rubrik.AddNFSArchivalLocation("NFS:GoSDK", "nas.gosdk.lab", "/export/rubrik", "SYSTEM")
+	if err != nil || nfsArchive != "No change required. The 'NFS:GoSDK' archive location is already configured on the Rubrik cluster." {
+		t.Errorf("AddNFSArchivalLocation() = %v, %v; want the no change message", nfsArchive, err)
+	}
+
+	_, err = rubrik.AddNFSArchivalLocation("NFS:GoSDK02", "nas.gosdk.lab", "/export/rubrik", "SYSTEM")
+	assertAPIError(t, "AddNFSArchivalLocation()", err)
+}
+
+func TestGetArchivalLocationsErrors(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{}, "GET /api/internal/archive/location")
+
+	_, err := rubrik.GetArchivalLocations()
+	assertAPIError(t, "GetArchivalLocations()", err)
+
+	_, err = rubrik.DeleteArchivalLocation("NFS:GoSDK")
+	assertAPIError(t, "DeleteArchivalLocation()", err)
+
+	_, err = rubrik.TriggerArchiveConsolidation("NFS:GoSDK")
+	assertAPIError(t, "TriggerArchiveConsolidation()", err)
+
+	rubrik = testClusterFailure(t, map[string]string{
+		"/api/internal/archive/location": `[]`,
+	})
+
+	if _, err := rubrik.GetArchivalLocations(); err == nil {
+		t.Error("expected an error when the archive location response is not an object")
+	}
+}
+
+// testClusterConfiguration contains the current configuration of a Rubrik cluster used by the idempotence and error tests.
+func testClusterConfiguration() map[string]string {
+	return map[string]string{
+		"/api/internal/replication/target":              `{"total": 1, "data": [{"id": "target-1", "targetClusterName": "rubrik-dr", "targetClusterAddress": "10.0.0.10"}]}`,
+		"/api/internal/user":                            `[{"id": "User:::1", "username": "svc-automation"}]`,
+		"/api/internal/ldap_service":                    `{"total": 1, "data": [{"id": "ldap-1", "name": "corp.local"}]}`,
+		"/api/v1/vmware/vm":                             `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+		"/api/internal/report":                          `{"total": 1, "data": [{"name": "Compliance", "id": "report-1"}]}`,
+		"/api/v1/vmware/vm/VirtualMachine:::1/snapshot": `{"total": 1, "data": [{"id": "snapshot-1", "date": "2026-10-01T15:30:00Z"}]}`,
+		"/api/internal/legal_hold/snapshot":             `{"total": 0, "data": []}`,
+		"/api/internal/network_throttle":                `{"total": 2, "data": [{"resourceId": "ReplicationEgress", "isEnabled": false}, {"resourceId": "ArchivalEgress", "isEnabled": true, "defaultThrottleLimit": 100}]}`,
+		"/api/internal/node_management/proxy_config":    `{"host": "proxy.gosdk.lab", "port": 3128, "protocol": "HTTP"}`,
+		"/api/v1/cluster/me":                            `{"id": "cluster-1", "version": "5.0.0", "timezone": {"timezone": "UTC"}}`,
+		"/api/internal/cluster/me/floating_ip":          `{"total": 1, "data": [{"ip": "10.0.0.50"}]}`,
+		"/api/internal/cluster/me/global_manager":       `{"isConnected": false}`,
+	}
+}
+
+func TestNoChangeRequired(t *testing.T) {
+	rubrik := testClusterFailure(t, testClusterConfiguration())
+	rubrik.PreserveNumbers = true
+
+	tests := []struct {
+		name string
+		call func() (interface{}, error)
+	}{
+		{"AddReplicationTarget", func() (interface{}, error) { return rubrik.AddReplicationTarget("10.0.0.10", "admin", "password") }},
+		{"CreateUser", func() (interface{}, error) { return rubrik.CreateUser("svc-automation", "password", "") }},
+		{"DeleteUser", func() (interface{}, error) { return rubrik.DeleteUser("svc-missing") }},
+		{"AddLDAPService", func() (interface{}, error) {
+			return rubrik.AddLDAPService("corp.local", "dc=corp,dc=local", "rubrik", "password", []string{"dc01.corp.local"}, nil)
+		}},
+		{"SetSnapshotLegalHold", func() (interface{}, error) { return rubrik.SetSnapshotLegalHold("vm01", "vmware", "snapshot-1", false) }},
+		{"SetReplicationThrottle", func() (interface{}, error) { return rubrik.SetReplicationThrottle(false, 0) }},
+		{"SetArchivalThrottle", func() (interface{}, error) { return rubrik.SetArchivalThrottle(true, 100) }},
+		{"ConfigureClusterProxy", func() (interface{}, error) {
+			return rubrik.ConfigureClusterProxy("proxy.gosdk.lab", 3128, "", "", "HTTP")
+		}},
+		{"SetClusterTimezone", func() (interface{}, error) { return rubrik.SetClusterTimezone("UTC") }},
+		{"SetClusterVIP", func() (interface{}, error) { return rubrik.SetClusterVIP([]string{"10.0.0.50"}) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.call()
+			if message, _ := result.(string); err != nil || strings.HasPrefix(message, "No change required.") == false {
+				t.Errorf("%s() = %v, %v; want a no change message", test.name, result, err)
+			}
+		})
+	}
+}
+
+func TestAPIErrorsAreReturned(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure string
+		call    func(rubrik *Credentials) error
+	}{
+		{"AddReplicationTarget", "POST /api/internal/replication/target", func(rubrik *Credentials) error {
+			_, err := rubrik.AddReplicationTarget("10.0.0.20", "admin", "password")
+			return err
+		}},
+		{"CreateUser", "POST /api/internal/user", func(rubrik *Credentials) error {
+			_, err := rubrik.CreateUser("svc-new", "password", "")
+			return err
+		}},
+		{"DeleteUser", "DELETE /api/internal/user/User:::1", func(rubrik *Credentials) error {
+			_, err := rubrik.DeleteUser("svc-automation")
+			return err
+		}},
+		{"AddLDAPService", "POST /api/internal/ldap_service", func(rubrik *Credentials) error {
+			_, err := rubrik.AddLDAPService("lab.local", "dc=lab,dc=local", "rubrik", "password", []string{"dc01.lab.local"}, nil)
+			return err
+		}},
+		{"GenerateAPIToken", "POST /api/v1/session", func(rubrik *Credentials) error {
+			_, err := rubrik.GenerateAPIToken(60, "automation")
+			return err
+		}},
+		{"RevokeAPIToken", "DELETE /api/v1/session/token-1", func(rubrik *Credentials) error {
+			_, err := rubrik.RevokeAPIToken("token-1")
+			return err
+		}},
+		{"GetEventSeries", "GET /api/v1/event/latest", func(rubrik *Credentials) error {
+			_, err := rubrik.GetEventSeries("vm01", "vmware", "Backup", 10)
+			return err
+		}},
+		{"GetFailedBackups", "GET /api/v1/event/latest", func(rubrik *Credentials) error {
+			_, err := rubrik.GetFailedBackups(24)
+			return err
+		}},
+		{"GetComplianceReport", "GET /api/internal/report/report-1", func(rubrik *Credentials) error {
+			_, err := rubrik.GetComplianceReport("Compliance")
+			return err
+		}},
+		{"ExportReportCSV", "GET /api/internal/report/report-1", func(rubrik *Credentials) error {
+			return rubrik.ExportReportCSV("Compliance", t.TempDir()+"/compliance.csv")
+		}},
+		{"GetProtectionTasks", "POST /api/internal/report/data_source/table", func(rubrik *Credentials) error {
+			_, err := rubrik.GetProtectionTasks("vm01", "vmware", 10)
+			return err
+		}},
+		{"DeleteSnapshot", "DELETE /api/v1/vmware/vm/snapshot/snapshot-1", func(rubrik *Credentials) error {
+			_, err := rubrik.DeleteSnapshot("vm01", "vmware", "snapshot-1")
+			return err
+		}},
+		{"SetSnapshotLegalHold", "POST /api/internal/legal_hold/snapshot", func(rubrik *Credentials) error {
+			_, err := rubrik.SetSnapshotLegalHold("vm01", "vmware", "snapshot-1", true)
+			return err
+		}},
+		{"GetArchivalThrottle", "GET /api/internal/network_throttle", func(rubrik *Credentials) error {
+			_, err := rubrik.GetArchivalThrottle()
+			return err
+		}},
+		{"SetReplicationThrottle", "PATCH /api/internal/network_throttle/ReplicationEgress", func(rubrik *Credentials) error {
+			_, err := rubrik.SetReplicationThrottle(true, 100)
+			return err
+		}},
+		{"ConfigureClusterProxy", "PATCH /api/internal/node_management/proxy_config", func(rubrik *Credentials) error {
+			_, err := rubrik.ConfigureClusterProxy("proxy02.gosdk.lab", 3128, "", "", "HTTP")
+			return err
+		}},
+		{"DeleteClusterProxy", "DELETE /api/internal/node_management/proxy_config", func(rubrik *Credentials) error {
+			_, err := rubrik.DeleteClusterProxy()
+			return err
+		}},
+		{"GetClusterTimezone", "GET /api/v1/cluster/me", func(rubrik *Credentials) error {
+			_, err := rubrik.GetClusterTimezone()
+			return err
+		}},
+		{"SetClusterTimezone", "PATCH /api/v1/cluster/me", func(rubrik *Credentials) error {
+			_, err := rubrik.SetClusterTimezone("America/Chicago")
+			return err
+		}},
+		{"SetClusterVIP", "PUT /api/internal/cluster/me/floating_ip", func(rubrik *Credentials) error {
+			_, err := rubrik.SetClusterVIP([]string{"10.0.0.51"})
+			return err
+		}},
+		{"RegisterWithPolaris", "POST /api/internal/cluster/me/global_manager", func(rubrik *Credentials) error {
+			_, err := rubrik.RegisterWithPolaris("gosdk.my.rubrik.com", "client-id", "client-secret")
+			return err
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rubrik := testClusterFailure(t, testClusterConfiguration(), test.failure)
+
+			assertAPIError(t, test.name+"()", test.call(rubrik))
+		})
+	}
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	lastSync := time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339)
+	olderSync := time.Now().UTC().Add(-5 * time.Hour).Format(time.RFC3339)
+
+	replicationEvent := func(id, objectName, status, location, eventTime string) string {
+		return `{"location": "` + location + `", "latestEvent": {"id": "` + id + `", "objectName": "` + objectName + `", "eventStatus": "` + status + `", "time": "` + eventTime + `"}}`
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/internal/replication/target":
+			w.Write([]byte(`{"total": 2, "data": [{"id": "target-1", "targetClusterName": "rubrik-dr", "targetClusterAddress": "10.0.0.10"}, {"id": "target-2", "targetClusterName": "", "targetClusterAddress": "10.0.0.20"}]}`))
+		case "/api/v1/event/latest":
+			if r.URL.Query().Get("event_type") != "Replication" {
+				t.Errorf("the Rubrik cluster received the query %q", r.URL.RawQuery)
+			}
+
+			switch r.URL.Query().Get("after_id") {
+			case "":
+				w.Write([]byte(`{"hasMore": true, "data": [` + replicationEvent("event-1", "vm01", "Failure", "rubrik-dr", lastSync) + `, ` + replicationEvent("event-2", "vm02", "Success", "rubrik-dr2", lastSync) + `]}`))
+			case "event-2":
+				w.Write([]byte(`{"hasMore": false, "data": [` + replicationEvent("event-3", "vm03", "Success", "rubrik-dr", olderSync) + `]}`))
+			default:
+				t.Errorf("unexpected after_id %q", r.URL.Query().Get("after_id"))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	replicationStatus, err := rubrik.GetReplicationStatus()
+	if err != nil {
+		t.Fatalf("GetReplicationStatus() returned an unexpected error: %s", err)
+	}
+
+	if len(replicationStatus) != 2 {
+		t.Fatalf("GetReplicationStatus() = %+v; want a status for each target", replicationStatus)
+	}
+
+	dr := replicationStatus[0]
+	if dr.LastSync.Format(time.RFC3339) != olderSync || dr.Lag < 5*time.Hour || len(dr.FailingSnapshots) != 1 || dr.FailingSnapshots[0] != "vm01" {
+		t.Errorf("GetReplicationStatus() returned %+v for rubrik-dr", dr)
+	}
+
+	if unnamed := replicationStatus[1]; unnamed.LastSync.IsZero() == false || len(unnamed.FailingSnapshots) != 0 {
+		t.Errorf("GetReplicationStatus() matched events to the unnamed target: %+v", unnamed)
+	}
+}
+
+func TestGetReplicationStatusPageLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/internal/replication/target":
+			w.Write([]byte(`{"total": 1, "data": [{"id": "target-1", "targetClusterName": "rubrik-dr", "targetClusterAddress": "10.0.0.10"}]}`))
+		case "/api/v1/event/latest":
+			requests++
+			w.Write([]byte(fmt.Sprintf(`{"hasMore": true, "data": [{"location": "rubrik-dr", "latestEvent": {"id": "event-%d", "eventStatus": "Success"}}]}`, requests)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	if _, err := rubrik.GetReplicationStatus(); err != nil {
+		t.Fatalf("GetReplicationStatus() returned an unexpected error: %s", err)
+	}
+
+	if requests != replicationEventPages {
+		t.Errorf("GetReplicationStatus() requested %d pages of events; want %d", requests, replicationEventPages)
+	}
+}
+
+func TestAssignUserRole(t *testing.T) {
+	var privileges map[string][]string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/internal/user":
+			w.Write([]byte(`[{"id": "User:::1", "username": "svc-automation"}]`))
+		case "/api/v1/sla_domain":
+			if r.URL.Query().Get("name") != "Gold" {
+				t.Errorf("the SLA Domain lookup received the query %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"total": 1, "data": [{"name": "Gold", "id": "sla-1"}]}`))
+		case "/api/internal/authorization/role/end_user":
+			if r.Method == "GET" {
+				w.Write([]byte(`{"data": [{"principal": "User:::1", "privileges": {"restore": ["sla-2"]}}]}`))
+				return
+			}
+
+			var config struct {
+				Privileges map[string][]string `json:"privileges"`
+			}
+			json.NewDecoder(r.Body).Decode(&config)
+			privileges = config.Privileges
+			w.Write([]byte(`{"data": []}`))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	if _, err := rubrik.AssignUserRole("svc-automation", "end_user", "sla", []string{"Gold"}); err != nil {
+		t.Fatalf("AssignUserRole() returned an unexpected error: %s", err)
+	}
+
+	if len(privileges["restore"]) != 1 || privileges["restore"][0] != "sla-1" {
+		t.Errorf("AssignUserRole() requested the privileges %v; want restore over sla-1", privileges)
+	}
+
+	// Without an objectType the objects are used as IDs and are never resolved as VM names
+	assignRole, err := rubrik.AssignUserRole("svc-automation", "end_user", "", []string{"sla-2"})
+	if err != nil || assignRole != "No change required. The user 'svc-automation' is already assigned the 'end_user' role." {
+		t.Errorf("AssignUserRole() = %v, %v; want the no change message", assignRole, err)
+	}
+
+	if _, err := rubrik.AssignUserRole("svc-automation", "end_user", "filesetTemplate", []string{"Template"}); err == nil {
+		t.Error("expected an error for an unsupported objectType")
+	}
+}
+
+func TestGetHelpersUnexpectedShapes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		call func(interface{}) error
+	}{
+		{"map from list", `[]`, func(v interface{}) error { _, err := getMap(v); return err }},
+		{"map from string", `{"data": "vm"}`, func(v interface{}) error { _, err := getMap(v, "data"); return err }},
+		{"slice missing key", `{"total": 1}`, func(v interface{}) error { _, err := getSlice(v, "data"); return err }},
+		{"slice from map", `{"data": {"id": "1"}}`, func(v interface{}) error { _, err := getSlice(v, "data"); return err }},
+		{"slice from null", `{"data": null}`, func(v interface{}) error { _, err := getSlice(v, "data"); return err }},
+		{"string from number", `{"id": 5}`, func(v interface{}) error { _, err := getString(v, "id"); return err }},
+		{"string through list", `{"links": [{"href": "x"}]}`, func(v interface{}) error { _, err := getString(v, "links", "href"); return err }},
+		{"string from scalar response", `"done"`, func(v interface{}) error { _, err := getString(v, "id"); return err }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.call(decodeJSON(t, test.body)); err == nil {
+				t.Errorf("expected an error for %s", test.body)
+			}
+		})
+	}
+}
+
+func TestGetHelpersNestedValues(t *testing.T) {
+	response := decodeJSON(t, `{"timezone": {"timezone": "UTC"}, "data": [1, 2]}`)
+
+	timezone, err := getString(response, "timezone", "timezone")
+	if err != nil || timezone != "UTC" {
+		t.Errorf("getString() = %q, %v; want \"UTC\", nil", timezone, err)
+	}
+
+	data, err := getSlice(response, "data")
+	if err != nil || len(data) != 2 {
+		t.Errorf("getSlice() = %v, %v; want 2 values", data, err)
+	}
+
+	if _, err := getMap(response); err != nil {
+		t.Errorf("getMap() returned an unexpected error: %s", err)
+	}
+}
+
+func TestObjectIDUnexpectedResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"data is not a list", `{"total": 1, "data": "vm01"}`},
+		{"data is missing", `{"total": 1}`},
+		{"name is not a string", `{"total": 1, "data": [{"name": 7, "id": "VirtualMachine:::1"}]}`},
+		{"id is missing", `{"total": 1, "data": [{"name": "vm01"}]}`},
+		{"object is not a map", `{"total": 1, "data": ["vm01"]}`},
+		{"response is a list", `[{"name": "vm01"}]`},
+		{"no matches", `{"total": 0, "data": []}`},
+		{"multiple matches", `{"total": 2, "data": [{"name": "vm01", "id": "1"}, {"name": "vm01", "id": "2"}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rubrik := testCluster(t, map[string]string{"/api/v1/vmware/vm": test.body})
+
+			if _, err := rubrik.ObjectID("vm01", "vmware"); err == nil {
+				t.Errorf("expected an error for %s", test.body)
+			}
+		})
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{
+		"/api/v1/vmware/vm": `{"total": 2, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}, {"name": "vm010", "id": "VirtualMachine:::2"}]}`,
+	})
+
+	vmID, err := rubrik.ObjectID("vm01", "vmware")
+	if err != nil {
+		t.Fatalf("ObjectID() returned an unexpected error: %s", err)
+	}
+
+	if vmID != "VirtualMachine:::1" {
+		t.Errorf("ObjectID() = %q; want \"VirtualMachine:::1\"", vmID)
+	}
+}
+
+func TestObjectIDReservedCharacters(t *testing.T) {
+	names := []string{"web & db 01", "vm01?is_relic=true", "finance#1", "50% done", "a+b=c", "vm01%26"}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if query.Get("name") != name || query.Get("is_relic") != "false" || len(query) != 3 {
+					t.Errorf("the Rubrik cluster received the query %q", r.URL.RawQuery)
+				}
+				w.Write([]byte(`{"total": 1, "data": [{"name": ` + strconv.Quote(name) + `, "id": "VirtualMachine:::1"}]}`))
+			}))
+			defer server.Close()
+
+			rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+			if vmID, err := rubrik.ObjectID(name, "vmware"); err != nil || vmID != "VirtualMachine:::1" {
+				t.Errorf("ObjectID() = %q, %v; want \"VirtualMachine:::1\", nil", vmID, err)
+			}
+		})
+	}
+}
+
+func TestGetEscape(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/vmware/vm/web db":   "/api/v1/vmware/vm/web%20db",
+		"/api/v1/vmware/vm/100%":     "/api/v1/vmware/vm/100%25",
+		"/api/v1/vmware/vm/vm01%26":  "/api/v1/vmware/vm/vm01%2526",
+		"/api/v1/vmware/vm/%zz,vm01": "/api/v1/vmware/vm/%25zz%2Cvm01",
+	}
+
+	for value, want := range tests {
+		if got := getEscape(value); got != want {
+			t.Errorf("getEscape(%q) = %q; want %q", value, got, want)
+		}
+	}
+}
+
+func TestEscapeRequestURL(t *testing.T) {
+	tests := map[string]string{
+		"https://rubrik/api/v1/vmware/vm/50% done":                            "https://rubrik/api/v1/vmware/vm/50%25%20done",
+		"https://rubrik/api/v1/vmware/vm?name=web+%26+db":                     "https://rubrik/api/v1/vmware/vm?name=web+%26+db",
+		"https://rubrik/api/v1/vmware/vm/web db?name=" + url.QueryEscape("%"): "https://rubrik/api/v1/vmware/vm/web%20db?name=%25",
+	}
+
+	for value, want := range tests {
+		if got := escapeRequestURL(value); got != want {
+			t.Errorf("escapeRequestURL(%q) = %q; want %q", value, got, want)
+		}
+	}
+}
+
+func TestObjectIDInvalidArguments(t *testing.T) {
+	rubrik := ConnectAPIToken("127.0.0.1:1", "token")
+
+	if _, err := rubrik.ObjectID("vm01", "hyperv"); err == nil {
+		t.Error("expected an error for an invalid objectType")
+	}
+
+	if _, err := rubrik.ObjectID("Template", "filesetTemplate"); err == nil {
+		t.Error("expected an error when the hostOS is missing")
+	}
+
+	if _, err := rubrik.ObjectID("Template", "filesetTemplate", "Solaris"); err == nil {
+		t.Error("expected an error for an invalid hostOS")
+	}
+}
+
+func TestAssignSLAUnexpectedResponse(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{
+		"/api/v1/sla_domain":                   `{"total": 1, "data": [{"name": "Gold", "id": "sla-1"}]}`,
+		"/api/v1/vmware/vm":                    `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+		"/api/v1/vmware/vm/VirtualMachine:::1": `{"effectiveSlaDomainId": null}`,
+	})
+
+	if _, err := rubrik.AssignSLA("vm01", "vmware", "Gold"); err == nil {
+		t.Error("expected an error when the VM summary does not contain an effectiveSlaDomainId")
+	}
+}
+
+func TestOnDemandSnapshotVMUnexpectedResponse(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{
+		"/api/v1/sla_domain":                            `{"total": 1, "data": [{"name": "Gold", "id": "sla-1"}]}`,
+		"/api/v1/vmware/vm":                             `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+		"/api/v1/vmware/vm/VirtualMachine:::1/snapshot": `{"id": "job-1", "links": []}`,
+	})
+
+	if _, err := rubrik.OnDemandSnapshotVM("vm01", "vmware", "Gold"); err == nil {
+		t.Error("expected an error when the snapshot response does not contain a job status URL")
+	}
+}
+
+func TestOnDemandSnapshotPhysicalUnexpectedResponse(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{
+		"/api/v1/host":             `{"total": 1, "data": [{"hostname": "host01", "id": "Host:::1"}]}`,
+		"/api/v1/fileset_template": `{"total": 1, "data": [{"name": "Template", "id": "FilesetTemplate:::1"}]}`,
+		"/api/v1/fileset":          `{"total": 1, "data": [{"name": "Template"}]}`,
+	})
+
+	if _, err := rubrik.OnDemandSnapshotPhysical("host01", "current", "Template", "Linux"); err == nil {
+		t.Error("expected an error when the fileset does not contain an id")
+	}
+}
+
+func TestConnect(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{})
+
+	if rubrik.Version != "5.0.0" {
+		t.Errorf("Version = %q; want \"5.0.0\"", rubrik.Version)
+	}
+}
+
+func TestConnectRejected(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errorType": "user_error", "message": "Incorrect username/password"}`))
+	}))
+	defer server.Close()
+
+	if _, err := Connect(strings.TrimPrefix(server.URL, "https://"), "admin", "wrong"); err == nil {
+		t.Error("expected an error when the Rubrik cluster rejects the credentials")
+	}
+}
+
+func TestReAuthenticate(t *testing.T) {
+	sessions := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/session":
+			sessions++
+			w.Write([]byte(`{"id": "session-1", "token": "new-token"}`))
+		case r.Header.Get("Authorization") != "Bearer new-token":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errorType": "user_error", "message": "Token has expired"}`))
+		default:
+			w.Write([]byte(`{"name": "cluster01"}`))
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "expired-token")
+	rubrik.Username = "admin"
+	rubrik.Password = "password"
+
+	if _, err := rubrik.commonAPI("GET", "v1", "/cluster/me", nil, 15); err == nil {
+		t.Fatal("expected an error when ReAuthenticate is false")
+	}
+
+	rubrik.ReAuthenticate = true
+	clusterSummary, err := rubrik.commonAPI("GET", "v1", "/cluster/me", nil, 15)
+	if err != nil {
+		t.Fatalf("commonAPI() returned an unexpected error: %s", err)
+	}
+
+	if clusterName, _ := getString(clusterSummary, "name"); clusterName != "cluster01" || sessions != 1 {
+		t.Errorf("cluster name = %q after %d sessions; want \"cluster01\" after 1 session", clusterName, sessions)
+	}
+
+	// The session request is still sent during a DryRun and is passed to the hooks and metrics
+	requests := []string{}
+	counter := &RequestCounter{}
+	rubrik.APIToken = "expired-token"
+	rubrik.DryRun = true
+	rubrik.Metrics = counter
+	rubrik.BeforeRequest = func(request *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s", request.Method, request.URL.Path))
+	}
+
+	if _, err := rubrik.commonAPI("GET", "v1", "/cluster/me", nil, 15); err != nil || sessions != 2 {
+		t.Fatalf("commonAPI() returned %v after %d sessions; want no error after 2 sessions", err, sessions)
+	}
+
+	if len(requests) != 3 || requests[1] != "POST /api/v1/session" {
+		t.Errorf("BeforeRequest received %v; want the session request between the GET requests", requests)
+	}
+
+	if stats := counter.Stats()[RequestMetric{Method: "POST", Endpoint: "v1/session", StatusClass: "2xx"}]; stats.Count != 1 {
+		t.Errorf("the session request was recorded %d times; want 1", stats.Count)
+	}
+}
+
+func TestReAuthenticateBadCredentials(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errorType": "user_error", "message": "Incorrect username/password"}`))
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "expired-token")
+	rubrik.Username = "admin"
+	rubrik.Password = "wrong"
+	rubrik.ReAuthenticate = true
+
+	if _, err := rubrik.commonAPI("GET", "v1", "/cluster/me", nil, 15); err == nil {
+		t.Error("expected an error when the credentials are rejected")
+	}
+
+	// The original request and a single session request
+	if requests != 2 {
+		t.Errorf("the Rubrik cluster received %d requests; want 2", requests)
+	}
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"name": "cluster01"}`))
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	if _, err := rubrik.commonAPI("POST", "internal", "/cluster/me/name", map[string]string{}, 15); err != nil {
+		t.Fatalf("commonAPI() returned an unexpected error: %s", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("the Rubrik cluster received %d requests; want 2", requests)
+	}
+}
+
+func TestRetryServerError(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+	rubrik.MaxRetries = 1
+
+	// Server errors are only retried for GET requests
+	if _, err := rubrik.commonAPI("POST", "internal", "/cluster/me/name", map[string]string{}, 15); err == nil {
+		t.Error("expected an error for a POST request that failed with a server error")
+	}
+
+	if _, err := rubrik.commonAPI("GET", "v1", "/cluster/me", nil, 15); err == nil {
+		t.Error("expected an error once the retries were exhausted")
+	}
+
+	if requests != 3 {
+		t.Errorf("the Rubrik cluster received %d requests; want 3", requests)
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		credentials Credentials
+		want        string
+	}{
+		{Credentials{NodeIP: "10.0.0.1"}, "https://10.0.0.1"},
+		{Credentials{NodeIP: "10.0.0.1", Port: 8443}, "https://10.0.0.1:8443"},
+		{Credentials{NodeIP: "gateway.rubrikgo.local", Scheme: "http", BasePath: "rubrik01/"}, "http://gateway.rubrikgo.local/rubrik01"},
+		{Credentials{NodeIP: "fd00::1", Port: 443, BasePath: "/rubrik01"}, "https://[fd00::1]:443/rubrik01"},
+	}
+
+	for _, test := range tests {
+		if got := test.credentials.baseURL(); got != test.want {
+			t.Errorf("baseURL() = %q; want %q", got, test.want)
+		}
+	}
+}
+
+func TestPaginatedData(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`{"hasMore": true, "data": [{"id": "vm-1"}, {"id": "vm-2"}]}`))
+		case "2":
+			w.Write([]byte(`{"hasMore": false, "data": [{"id": "vm-3"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	data, err := rubrik.paginatedData("v1", "/vmware/vm?is_relic=false", 15)
+	if err != nil {
+		t.Fatalf("paginatedData() returned an unexpected error: %s", err)
+	}
+
+	if len(data) != 3 {
+		t.Errorf("paginatedData() returned %d objects; want 3", len(data))
+	}
+}
+
+func TestPreserveNumbers(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{
+		"/api/internal/stats/system_storage": `{"total": 9007199254740993, "used": 1.5e3}`,
+	})
+
+	rubrik.PreserveNumbers = true
+	storage, err := rubrik.commonAPI("GET", "internal", "/stats/system_storage", nil, 15)
+	if err != nil {
+		t.Fatalf("commonAPI() returned an unexpected error: %s", err)
+	}
+
+	total, _ := apiValue(storage, []string{"total"})
+	if totalBytes, err := Int64Value(total); err != nil || totalBytes != 9007199254740993 {
+		t.Errorf("Int64Value() = %d, %v; want 9007199254740993, nil", totalBytes, err)
+	}
+
+	used, _ := apiValue(storage, []string{"used"})
+	if usedBytes, err := Int64Value(used); err != nil || usedBytes != 1500 {
+		t.Errorf("Int64Value() = %d, %v; want 1500, nil", usedBytes, err)
+	}
+}
+
+func TestNumberValues(t *testing.T) {
+	if _, err := Int64Value(1.5); err == nil {
+		t.Error("expected an error converting 1.5 to an int64")
+	}
+
+	if _, err := Int64Value("5"); err == nil {
+		t.Error("expected an error converting a string to an int64")
+	}
+
+	if value, err := Float64Value(json.Number("2.5")); err != nil || value != 2.5 {
+		t.Errorf("Float64Value() = %v, %v; want 2.5, nil", value, err)
+	}
+
+	if numberEq(float64(300000), 300000) == false || numberEq(json.Number("300000"), 300000) == false {
+		t.Error("numberEq() should match both float64 and json.Number values")
+	}
+}
+
+func TestSLAAPIVersion(t *testing.T) {
+	tests := map[string]string{
+		"4.2.1-p3-1234": "v1",
+		"5.0.0-p1-1686": "v2",
+		"5.3.2-p2-9130": "v2",
+	}
+
+	for version, want := range tests {
+		rubrik := &Credentials{Version: version}
+		if apiVersion, err := rubrik.slaAPIVersion(15); err != nil || apiVersion != want {
+			t.Errorf("slaAPIVersion() for %s = %q, %v; want %q, nil", version, apiVersion, err, want)
+		}
+	}
+}
+
+func TestSLAFrequenciesEqual(t *testing.T) {
+	frequencies := []SLAFrequency{{"Daily", 1, 30}, {"Monthly", 1, 12}}
+
+	v1Frequencies, _ := BuildSLAFrequencies("v1", frequencies...)
+	current := decodeJSON(t, `[{"timeUnit": "Monthly", "frequency": 1, "retention": 12}, {"timeUnit": "Daily", "frequency": 1, "retention": 30}]`)
+	if slaFrequenciesEqual(current, v1Frequencies) == false {
+		t.Error("expected the v1 frequencies to match regardless of order")
+	}
+
+	v2Frequencies, _ := BuildSLAFrequencies("v2", frequencies...)
+	current = decodeJSON(t, `{"daily": {"frequency": 1, "retention": 30}, "monthly": {"frequency": 1, "retention": 12, "dayOfMonth": "LastDay"}}`)
+	if slaFrequenciesEqual(current, v2Frequencies) == false {
+		t.Error("expected the v2 frequencies to match")
+	}
+
+	current = decodeJSON(t, `{"daily": {"frequency": 1, "retention": 7}, "monthly": {"frequency": 1, "retention": 12, "dayOfMonth": "LastDay"}}`)
+	if slaFrequenciesEqual(current, v2Frequencies) {
+		t.Error("expected a changed retention not to match")
+	}
+}
+
+func TestRawRequest(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/api/v1/vmware/vm/request/job-1")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id": "job-1"}`))
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	response, body, err := rubrik.RawRequest("POST", "v1", "/vmware/vm/snapshot", map[string]string{}, 15)
+	if err != nil {
+		t.Fatalf("RawRequest() returned an unexpected error: %s", err)
+	}
+
+	if response.StatusCode != http.StatusAccepted || response.Header.Get("Location") != "/api/v1/vmware/vm/request/job-1" {
+		t.Errorf("RawRequest() returned %d with Location %q", response.StatusCode, response.Header.Get("Location"))
+	}
+
+	if string(body) != `{"id": "job-1"}` {
+		t.Errorf("RawRequest() returned the body %q", body)
+	}
+}
+
+func TestEndManagedVolumeSnapshotError(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/internal/managed_volume":
+			w.Write([]byte(`{"total": 1, "data": [{"name": "mv01", "id": "ManagedVolume:::1"}]}`))
+		case "/api/internal/managed_volume/ManagedVolume:::1":
+			w.Write([]byte(`{"id": "ManagedVolume:::1", "isWritable": true, "configuredSlaDomainId": "sla-1"}`))
+		case "/api/internal/managed_volume/ManagedVolume:::1/end_snapshot":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorType": "user_error", "message": "The managed volume is not in a writable state"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	endSnapshot, err := rubrik.EndManagedVolumeSnapshot("mv01", "current")
+	if err == nil {
+		t.Fatalf("expected an error when the end_snapshot request fails, got %v", endSnapshot)
+	}
+
+	if apiError, ok := err.(*APIError); ok != true || apiError.StatusCode != http.StatusBadRequest {
+		t.Errorf("EndManagedVolumeSnapshot() returned %v; want a 400 APIError", err)
+	}
+}
+
+func TestManagedVolumeSnapshotEndsAfterWriteError(t *testing.T) {
+	endSnapshots := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/internal/managed_volume":
+			w.Write([]byte(`{"total": 1, "data": [{"name": "mv01", "id": "ManagedVolume:::1"}]}`))
+		case "/api/internal/managed_volume/ManagedVolume:::1":
+			w.Write([]byte(`{"id": "ManagedVolume:::1", "isWritable": ` + strconv.FormatBool(endSnapshots == 0) + `, "configuredSlaDomainId": "sla-1"}`))
+		case "/api/internal/managed_volume/ManagedVolume:::1/end_snapshot":
+			endSnapshots++
+			w.Write([]byte(`{"id": "snapshot-1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	_, err := rubrik.ManagedVolumeSnapshot("mv01", "current", func() error {
+		return errors.New("disk full")
+	})
+	if err == nil || strings.Contains(err.Error(), "disk full") == false {
+		t.Errorf("ManagedVolumeSnapshot() returned %v; want the write error", err)
+	}
+
+	if endSnapshots != 1 {
+		t.Errorf("the snapshot was ended %d times; want 1", endSnapshots)
+	}
+}
+
+func TestGetAllVMs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("is_relic") != "false" {
+			t.Errorf("the Rubrik cluster received the query %q", r.URL.RawQuery)
+		}
+
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`{"hasMore": true, "data": [{"id": "VirtualMachine:::1", "name": "vm01", "effectiveSlaDomainId": "sla-1", "effectiveSlaDomainName": "Gold", "powerStatus": "poweredOn"}]}`))
+		default:
+			w.Write([]byte(`{"hasMore": false, "data": [{"id": "VirtualMachine:::2", "name": "vm02", "effectiveSlaDomainId": "UNPROTECTED", "powerStatus": "poweredOff"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	vms, err := rubrik.GetAllVMs(false)
+	if err != nil {
+		t.Fatalf("GetAllVMs() returned an unexpected error: %s", err)
+	}
+
+	if len(vms) != 2 || vms[0].Protected == false || vms[1].Protected || vms[1].PowerStatus != "poweredOff" {
+		t.Errorf("GetAllVMs() = %+v", vms)
+	}
+}
+
+func TestParseSnapshotWindow(t *testing.T) {
+	window, err := parseSnapshotWindow(decodeJSON(t, `{"startTimeAttributes": {"hour": 22, "minutes": 30}, "durationInHours": 8}`))
+	if err != nil {
+		t.Fatalf("parseSnapshotWindow() returned an unexpected error: %s", err)
+	}
+
+	if window.StartTime != "22:30" || window.EndTime != "06:30" || len(window.DaysOfWeek) != 7 {
+		t.Errorf("parseSnapshotWindow() = %+v", window)
+	}
+
+	window, err = parseSnapshotWindow(decodeJSON(t, `{"startTimeAttributes": {"hour": 1, "minutes": 0, "dayOfWeek": 7}, "durationInHours": 4}`))
+	if err != nil || len(window.DaysOfWeek) != 1 || window.DaysOfWeek[0] != "Saturday" {
+		t.Errorf("parseSnapshotWindow() = %+v, %v; want a Saturday window", window, err)
+	}
+
+	if _, err := parseSnapshotWindow(decodeJSON(t, `{"durationInHours": 4}`)); err == nil {
+		t.Error("expected an error for a window without a start time")
+	}
+}
+
+func TestGetObjectStorageStats(t *testing.T) {
+	rubrik := testCluster(t, map[string]string{
+		"/api/v1/vmware/vm": `{"total": 1, "data": [{"name": "vm01", "id": "VirtualMachine:::1"}]}`,
+		"/api/internal/stats/per_vm_storage/VirtualMachine:::1": `{"id": "VirtualMachine:::1", "logicalBytes": 400, "ingestedBytes": 200, "exclusivePhysicalBytes": 100, "sharedPhysicalBytes": 20}`,
+	})
+
+	storage, err := rubrik.GetObjectStorageStats("vm01", "vmware")
+	if err != nil {
+		t.Fatalf("GetObjectStorageStats() returned an unexpected error: %s", err)
+	}
+
+	if storage.LogicalBytes != 400 || storage.PhysicalBytes != 100 || storage.DataReductionPercent != 75 {
+		t.Errorf("GetObjectStorageStats() = %+v", storage)
+	}
+}
+
+func TestGetActiveBackups(t *testing.T) {
+	startTime := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+
+	rubrik := testCluster(t, map[string]string{
+		"/api/v1/event/latest":                `{"hasMore": false, "data": [{"latestEvent": {"id": "event-1", "eventSeriesId": "series-1", "objectName": "vm01", "objectType": "VmwareVm", "eventStatus": "Running"}}]}`,
+		"/api/internal/event_series/series-1": `{"progressPercentage": 25, "startTime": "` + startTime + `"}`,
+	})
+
+	activeBackups, err := rubrik.GetActiveBackups()
+	if err != nil {
+		t.Fatalf("GetActiveBackups() returned an unexpected error: %s", err)
+	}
+
+	if len(activeBackups) != 1 || activeBackups[0].ObjectName != "vm01" || activeBackups[0].ProgressPercent != 25 {
+		t.Fatalf("GetActiveBackups() = %+v", activeBackups)
+	}
+
+	// 25% in an hour leaves roughly three more hours
+	remaining := time.Until(activeBackups[0].EstimatedCompletion)
+	if remaining < 2*time.Hour+55*time.Minute || remaining > 3*time.Hour+5*time.Minute {
+		t.Errorf("the estimated completion is %s away; want about 3h", remaining)
+	}
+}
+
+func TestDialTimeout(t *testing.T) {
+	rubrik := ConnectAPIToken("10.255.255.1", "token")
+
+	if transport := rubrik.httpClient(60).Transport.(*http.Transport); transport.DialContext != nil {
+		t.Error("expected the default dialer when DialTimeout is not set")
+	}
+
+	rubrik.DialTimeout = 1
+	client := rubrik.httpClient(60)
+	if client.Timeout != 60*time.Second || client.Transport.(*http.Transport).DialContext == nil {
+		t.Errorf("httpClient() has a %s timeout and DialContext %v", client.Timeout, client.Transport.(*http.Transport).DialContext != nil)
+	}
+}
+
+func TestSetLoggerRedactsCredentials(t *testing.T) {
+	server := testServer(t, map[string]string{
+		"/api/v1/cluster/me": `{"id": "cluster-1", "version": "5.0.0"}`,
+		"/api/internal/user": `[]`,
+	}, nil)
+
+	rubrik, err := Connect(strings.TrimPrefix(server.URL, "https://"), "admin", "cluster-password")
+	if err != nil {
+		t.Fatalf("Connect() returned an unexpected error: %s", err)
+	}
+
+	var output bytes.Buffer
+	rubrik.SetLogger(log.New(&output, "", 0))
+
+	if _, err := rubrik.CreateUser("svc-automation", "user-password", ""); err != nil {
+		t.Fatalf("CreateUser() returned an unexpected error: %s", err)
+	}
+
+	token := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "api-token")
+	token.SetLogger(log.New(&output, "", 0))
+
+	if _, err := token.commonAPI("GET", "v1", "/cluster/me", nil, 15); err != nil {
+		t.Fatalf("commonAPI() returned an unexpected error: %s", err)
+	}
+
+	logged := output.String()
+	if strings.Contains(logged, "POST") == false || strings.Contains(logged, "[REDACTED]") == false {
+		t.Fatalf("the log does not contain the redacted CreateUser() request:\n%s", logged)
+	}
+
+	basicAuth := base64.StdEncoding.EncodeToString([]byte("admin:cluster-password"))
+	for _, secret := range []string{"user-password", "cluster-password", basicAuth, "api-token", "Authorization"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("the log contains %q:\n%s", secret, logged)
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	tests := []struct {
+		apiVersion  string
+		apiEndpoint string
+		want        string
+	}{
+		{"v1", "/cluster/me", "v1/cluster/me"},
+		{"v1", "/vmware/vm?name=vm01", "v1/vmware/vm"},
+		{"v1", "/vmware/vm/VirtualMachine:::c4a1fc4a-6a2e-4f69-b9e5-6c4d4e0e40c1-vm-1336/snapshot", "v1/vmware/vm/{id}/snapshot"},
+		{"internal", "/managed_volume/ManagedVolume%3A%3A%3A1/begin_snapshot", "internal/managed_volume/{id}/begin_snapshot"},
+		{"v1", "/vmware/vm/snapshot/2c0a7ef4-3b43-4d56-9d67-5d1b3dd7e2a5", "v1/vmware/vm/snapshot/{id}"},
+		{"internal", "/node/1/stats", "internal/node/{id}/stats"},
+		{"v1", "/fileset_template/bulk", "v1/fileset_template/bulk"},
+	}
+
+	for _, test := range tests {
+		if got := metricsEndpoint(test.apiVersion, test.apiEndpoint); got != test.want {
+			t.Errorf("metricsEndpoint(%q, %q) = %q; want %q", test.apiVersion, test.apiEndpoint, got, test.want)
+		}
+	}
+}
+
+func TestWaitForJob(t *testing.T) {
+	jobStatusURL := "https://rubrik/api/v1/vmware/vm/request/job-1"
+
+	tests := []struct {
+		jobStatus string
+		wantErr   string
+	}{
+		{`{"id": "job-1", "status": "SUCCEEDED"}`, ""},
+		{`{"id": "job-1", "status": "FAILED", "error": {"message": "The snapshot failed"}}`, "The job finished with a FAILED status: The snapshot failed"},
+		{`{"id": "job-1", "status": "RUNNING"}`, "Timed out waiting for the job"},
+	}
+
+	for _, test := range tests {
+		rubrik := testClusterFailure(t, map[string]string{
+			"/api/v1/vmware/vm/request/job-1": test.jobStatus,
+		})
+		rubrik.JobPollInterval = time.Millisecond
+		rubrik.JobMaxWait = 10 * time.Millisecond
+
+		jobStatus, err := rubrik.WaitForJob(jobStatusURL)
+		if len(test.wantErr) == 0 {
+			if status, _ := getString(jobStatus, "status"); err != nil || status != "SUCCEEDED" {
+				t.Errorf("WaitForJob() = %v, %v; want the SUCCEEDED job status", jobStatus, err)
+			}
+			continue
+		}
+
+		if err == nil || strings.Contains(err.Error(), test.wantErr) != true {
+			t.Errorf("WaitForJob() with %s returned %v; want an error containing %q", test.jobStatus, err, test.wantErr)
+		}
+	}
+}
+
+func TestMSSQLLiveMountTimeout(t *testing.T) {
+	rubrik := testClusterFailure(t, map[string]string{
+		"/api/v1/cluster/me":                            `{"id": "cluster-1", "timezone": {"timezone": "UTC"}}`,
+		"/api/v1/mssql/db":                              `{"data": [{"id": "MssqlDatabase:::1", "name": "db01"}]}`,
+		"/api/v1/mssql/instance":                        `{"data": [{"id": "MssqlInstance:::1", "name": "sql01"}]}`,
+		"/api/v1/mssql/db/MssqlDatabase:::1/snapshot":   `{"data": [{"id": "snapshot-1", "date": "2019-01-01T12:00:00Z"}]}`,
+		"POST /api/v1/mssql/db/MssqlDatabase:::1/mount": `{"id": "job-1", "links": [{"href": "https://rubrik/api/v1/mssql/request/job-1"}]}`,
+		"/api/v1/mssql/request/job-1":                   `{"id": "job-1", "status": "RUNNING"}`,
+	})
+	rubrik.JobPollInterval = time.Millisecond
+	rubrik.JobMaxWait = 10 * time.Millisecond
+
+	mountResult, err := rubrik.MSSQLLiveMount("db01", "01-01-2019 12:30 PM", "sql01", "db01-mount")
+	if err == nil || strings.Contains(err.Error(), "Timed out waiting for the job") != true {
+		t.Errorf("MSSQLLiveMount() returned %v; want a timeout error", err)
+	}
+
+	if mountResult == nil || mountResult.JobStatusURL != "https://rubrik/api/v1/mssql/request/job-1" || mountResult.SnapshotID != "snapshot-1" {
+		t.Errorf("MSSQLLiveMount() = %+v; want the snapshot ID and job status URL of the mount", mountResult)
+	}
+}
+
+func TestClusterGroupForEach(t *testing.T) {
+	group := NewClusterGroup(&Credentials{NodeIP: "rubrik01"}, &Credentials{NodeIP: "rubrik01"}, &Credentials{NodeIP: "rubrik02"})
+
+	clusterErrors := group.ForEach(func(rubrik *Credentials) error {
+		if rubrik == group.Clusters[2] {
+			panic("unexpected response")
+		}
+		if rubrik == group.Clusters[0] {
+			return nil
+		}
+		return errors.New("The request was rejected")
+	})
+
+	if len(clusterErrors) != 2 {
+		t.Fatalf("ForEach() = %v; want an error for clusters 1 and 2", clusterErrors)
+	}
+
+	if err := clusterErrors[1]; err == nil || err.Error() != "The request was rejected" {
+		t.Errorf("ForEach() error for cluster 1 = %v; want the returned error", err)
+	}
+
+	if err := clusterErrors[2]; err == nil || strings.Contains(err.Error(), "unexpected response") != true {
+		t.Errorf("ForEach() error for cluster 2 = %v; want the recovered panic", err)
+	}
+}
+
+func TestClusterGroupForEachConcurrency(t *testing.T) {
+	group := NewClusterGroup()
+	for i := 0; i < 10; i++ {
+		group.Clusters = append(group.Clusters, &Credentials{NodeIP: fmt.Sprintf("rubrik%02d", i)})
+	}
+	group.Concurrency = 3
+
+	var running, maxRunning, calls int32
+	clusterErrors := group.ForEach(func(rubrik *Credentials) error {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	if len(clusterErrors) != 0 || calls != 10 {
+		t.Errorf("ForEach() = %v after %d calls; want no errors after 10 calls", clusterErrors, calls)
+	}
+
+	if maxRunning > 3 {
+		t.Errorf("ForEach() ran %d calls at the same time; want at most 3", maxRunning)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+		w.Write([]byte(`{"id": "cluster-1", "name": "rubrik01"}`))
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+	rubrik.DryRun = true
+
+	for _, callType := range []string{"POST", "PATCH", "DELETE"} {
+		apiResponse, err := rubrik.commonAPI(callType, "v1", "/cluster/me", map[string]string{"name": "rubrik02"}, 15)
+		dryRunResponse, _ := apiResponse.(map[string]interface{})
+		if dryRun, _ := dryRunResponse["dryRun"].(bool); err != nil || dryRun != true {
+			t.Errorf("commonAPI(%q) = %v, %v; want the dry run response", callType, apiResponse, err)
+		}
+	}
+
+	name, err := rubrik.GetClusterName()
+	if err != nil || name != "rubrik01" {
+		t.Errorf("GetClusterName() = %q, %v; want \"rubrik01\", nil", name, err)
+	}
+
+	if len(requests) != 1 || requests[0] != "GET /api/v1/cluster/me" {
+		t.Errorf("the Rubrik cluster received %v; want only the GET request", requests)
+	}
+}
+
+func TestExpireSnapshotsBySLA(t *testing.T) {
+	snapshots := []string{}
+	for i := 0; i < 2*pageSize+5; i++ {
+		snapshots = append(snapshots, fmt.Sprintf(`{"id": "snapshot-%d", "slaId": "sla-1", "date": "2019-01-01T12:00:00Z"}`, i))
+	}
+	snapshots = append(snapshots, `{"id": "snapshot-recent", "slaId": "sla-1", "date": "`+time.Now().UTC().Format(time.RFC3339)+`"}`)
+	snapshots = append(snapshots, `{"id": "snapshot-other", "slaId": "sla-2", "date": "2019-01-01T12:00:00Z"}`)
+
+	queries := []string{}
+	batches := [][]string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sla_domain":
+			w.Write([]byte(`{"data": [{"id": "sla-1", "name": "Gold"}]}`))
+		case "/api/v1/vmware/vm":
+			queries = append(queries, r.URL.RawQuery)
+			w.Write([]byte(`{"hasMore": false, "data": [{"id": "VirtualMachine:::1", "isRelic": true}]}`))
+		case "/api/v1/mssql/db", "/api/internal/managed_volume":
+			queries = append(queries, r.URL.RawQuery)
+			w.Write([]byte(`{"hasMore": false, "data": []}`))
+		case "/api/v1/vmware/vm/VirtualMachine:::1/snapshot":
+			w.Write([]byte(`{"data": [` + strings.Join(snapshots, ",") + `]}`))
+		case "/api/internal/unmanaged_object/VirtualMachine:::1/snapshot/bulk_delete":
+			var config struct {
+				SnapshotIDs []string `json:"snapshotIds"`
+			}
+			json.NewDecoder(r.Body).Decode(&config)
+			batches = append(batches, config.SnapshotIDs)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rubrik := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+
+	expiredSnapshots, err := rubrik.ExpireSnapshotsBySLA("Gold", 30)
+	if err != nil {
+		t.Fatalf("ExpireSnapshotsBySLA() returned an unexpected error: %s", err)
+	}
+
+	if bulkDeletes, _ := expiredSnapshots.([]interface{}); len(bulkDeletes) != 3 {
+		t.Errorf("ExpireSnapshotsBySLA() = %v; want 3 bulk delete responses", expiredSnapshots)
+	}
+
+	if len(batches) != 3 || len(batches[0]) != pageSize || len(batches[1]) != pageSize || len(batches[2]) != 5 {
+		t.Fatalf("the snapshots were expired in %d batches; want batches of %d, %d, and 5", len(batches), pageSize, pageSize)
+	}
+
+	if batches[0][0] != "snapshot-0" || batches[2][4] != fmt.Sprintf("snapshot-%d", 2*pageSize+4) {
+		t.Errorf("the snapshots were expired in the batches %v; want every old snapshot of the SLA Domain in order", batches)
+	}
+
+	for _, query := range queries {
+		if strings.Contains(query, "is_relic") {
+			t.Errorf("the protected objects were requested with %q; want relics to be included", query)
+		}
+	}
+
+	snapshots = append(snapshots, `{"id": "snapshot-invalid", "slaId": "sla-1", "date": "12-31-2018"}`)
+	batches = nil
+
+	_, err = rubrik.ExpireSnapshotsBySLA("Gold", 30)
+	if err == nil || err.Error() != "Unable to read the date of the snapshot 'snapshot-invalid'." {
+		t.Errorf("ExpireSnapshotsBySLA() returned %v; want an error for the invalid snapshot date", err)
+	}
+
+	if len(batches) != 0 {
+		t.Errorf("%d batches of snapshots were expired; want none when a snapshot date is invalid", len(batches))
+	}
+}
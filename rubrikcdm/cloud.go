@@ -1,10 +1,13 @@
 package rubrikcdm
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // AddAWSNativeAccount enables the management and protection of Amazon Elastic Compute Cloud (Amazon EC2) instances. The "regionalBoltNetworkConfigs"
@@ -33,7 +36,7 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 
 	c.ClusterVersionCheck(4.2)
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
 	validAWSRegions := map[string]bool{
 		"ap-south-1":     true,
@@ -97,7 +100,8 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 
 }
 
-// AWSS3CloudOutRSA configures a new AWS S3 archive target using a RSA Key for encryption.
+// AddAWSS3ArchivalLocation configures a new AWS S3 archive target named "name" that stores its data in the "awsBucket" and encrypts it
+// with the PEM encoded "rsaKey".
 //
 // Valid "awsRegion" choices are:
 //
@@ -106,90 +110,50 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 //
 // Valid "storageClass" choices are:
 //
-//	ap-standard-1, standard_ia, and reduced_redundancy
+//	standard, standard_ia, onezone_ia, and reduced_redundancy (case insensitive)
 //
 // The function will return one of the following:
-//	- No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
+//	- No change required. The '{name}' archive location is already configured on the Rubrik cluster.
 //
 //	- The full API response for POST /internal/archive/object_store.
-func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey string, timeout ...int) interface{} {
-
-	httpTimeout := httpTimeout(timeout)
-
-	validAWSRegions := map[string]bool{
-		"ap-south-1":     true,
-		"ap-northeast-3": true,
-		"ap-northeast-2": true,
-		"ap-southeast-1": true,
-		"ap-southeast-2": true,
-		"ap-northeast-1": true,
-		"ca-central-1":   true,
-		"cn-north-1":     true,
-		"cn-northwest-1": true,
-		"eu-central-1":   true,
-		"eu-west-1":      true,
-		"eu-west-2":      true,
-		"eu-west-3":      true,
-		"us-west-1":      true,
-		"us-east-1":      true,
-		"us-east-2":      true,
-		"us-west-2":      true,
-	}
-
-	validStorageClass := map[string]bool{
-		"standard":           true,
-		"standard_ia":        true,
-		"reduced_redundancy": true,
-	}
+func (c *Credentials) AddAWSS3ArchivalLocation(name, awsBucket, awsRegion, awsAccessKey, awsSecretKey, storageClass, rsaKey string, timeout ...int) (interface{}, error) {
 
-	if validAWSRegions[awsRegion] == false {
-		log.Fatalf("Error: %s is not a valid AWS Region.", awsRegion)
-	}
-
-	if validStorageClass[storageClass] == false {
-		log.Fatalf("Error: %s is not a valid 'storageClass'. Please use 'standard', 'standard_ia', or 'reduced_redundancy'.", storageClass)
-	}
+	httpTimeout := c.httpTimeout(timeout)
 
-	config := map[string]string{}
-	config["name"] = archiveName
-	config["bucket"] = strings.ToLower(awsBucketName)
-	config["defaultRegion"] = awsRegion
-	config["storageClass"] = strings.ToUpper(storageClass)
-	config["accessKey"] = awsAccessKey
-	config["secretKey"] = awsSecretKey
-	config["objectStoreType"] = "S3"
-	config["pemFileContent"] = rsaKey
-
-	// Create a simplified config that only includes the values returned by Rubrik that can be used for idempotence check
-	redactedConfig := map[string]interface{}{}
-	redactedConfig["name"] = archiveName
-	redactedConfig["bucket"] = strings.ToLower(awsBucketName)
-	redactedConfig["defaultRegion"] = awsRegion
-	redactedConfig["storageClass"] = strings.ToUpper(storageClass)
-	redactedConfig["accessKey"] = awsAccessKey
-	redactedConfig["objectStoreType"] = "S3"
-
-	archivesOnCluster := c.Get("internal", "/archive/object_store", httpTimeout).(map[string]interface{})["data"]
-
-	for _, v := range archivesOnCluster.([]interface{}) {
-		archiveDefinition := (v.(interface{}).(map[string]interface{})["definition"])
-		delete(archiveDefinition.(map[string]interface{}), "definition")
+	encryption := map[string]string{}
+	encryption["pemFileContent"] = rsaKey
 
-		archivePresent := reflect.DeepEqual(redactedConfig, archiveDefinition)
-
-		if archivePresent {
-			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName)
-		}
+	return c.awsS3ArchivalLocation(name, awsBucket, awsRegion, awsAccessKey, awsSecretKey, storageClass, encryption, httpTimeout)
+}
 
-		if archiveDefinition.(map[string]interface{})["objectStoreType"] == "S3" && archiveDefinition.(map[string]interface{})["name"] == archiveName {
+// AWSS3CloudOutRSA configures a new AWS S3 archive target using a RSA Key for encryption.
+//
+// Valid "awsRegion" choices are:
+//
+//	ap-south-1,ap-northeast-3, ap-northeast-2, ap-southeast-1, ap-southeast-2, ap-northeast-1, ca-central-1, cn-north-1, cn-northwest-1, eu-central-1, eu-west-1,
+//	eu-west-2, eu-west-3, us-west-1, us-east-1, us-east-2, and us-west-2.
+//
+// Valid "storageClass" choices are:
+//
+//	standard, standard_ia, onezone_ia, and reduced_redundancy (case insensitive)
+//
+// The function will return one of the following:
+//	- No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
+//
+//	- The full API response for POST /internal/archive/object_store.
+func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey string, timeout ...int) interface{} {
 
-			log.Fatalf(fmt.Sprintf("Error: An archive location with the name '%s' already exists. Please enter a unique 'archiveName'", archiveName))
+	httpTimeout := c.httpTimeout(timeout)
 
-		}
+	encryption := map[string]string{}
+	encryption["pemFileContent"] = rsaKey
 
+	awsS3CloudOut, err := c.awsS3ArchivalLocation(archiveName, awsBucketName, awsRegion, awsAccessKey, awsSecretKey, storageClass, encryption, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
 	}
 
-	return c.Post("internal", "/archive/object_store", config, httpTimeout)
+	return awsS3CloudOut
 }
 
 // AWSS3CloudOutKMS configures a new AWS S3 archive target using a AWS KMS Master Key ID for encryption.
@@ -201,7 +165,7 @@ func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName,
 //
 // Valid "storageClass" choices are:
 //
-//	ap-standard-1, standard_ia, and reduced_redundancy
+//	standard, standard_ia, onezone_ia, and reduced_redundancy (case insensitive)
 //
 // The function will return one of the following:
 //	- No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
@@ -209,7 +173,22 @@ func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName,
 //	- The full API response for POST /internal/archive/object_store/{archiveID}
 func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, kmsMasterKeyID string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
+
+	encryption := map[string]string{}
+	encryption["kmsMasterKeyId"] = kmsMasterKeyID
+
+	awsS3CloudOut, err := c.awsS3ArchivalLocation(archiveName, awsBucketName, awsRegion, awsAccessKey, awsSecretKey, storageClass, encryption, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return awsS3CloudOut
+}
+
+// awsS3ArchivalLocation configures a new AWS S3 archive target unless an archive location with the same definition already exists. The
+// "encryption" settings (pemFileContent or kmsMasterKeyId) are added to the request as is.
+func (c *Credentials) awsS3ArchivalLocation(name, awsBucket, awsRegion, awsAccessKey, awsSecretKey, storageClass string, encryption map[string]string, timeout int) (interface{}, error) {
 
 	validAWSRegions := map[string]bool{
 		"ap-south-1":     true,
@@ -235,56 +214,71 @@ func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName,
 		"standard":           true,
 		"standard_ia":        true,
 		"reduced_redundancy": true,
+		"onezone_ia":         true,
 	}
 
 	if validAWSRegions[awsRegion] == false {
-		log.Fatalf("Error: %s is not a valid AWS Region.", awsRegion)
+		return nil, fmt.Errorf("%s is not a valid AWS Region.", awsRegion)
 	}
 
-	if validStorageClass[storageClass] == false {
-		log.Fatalf("Error: %s is not a valid 'storageClass'. Please use 'standard', 'standard_ia', or 'reduced_redundancy'.", storageClass)
+	if validStorageClass[strings.ToLower(storageClass)] == false {
+		return nil, fmt.Errorf("%s is not a valid 'storageClass'. Please use 'standard', 'standard_ia', 'onezone_ia', or 'reduced_redundancy'.", storageClass)
 	}
 
 	config := map[string]string{}
-	config["name"] = archiveName
-	config["bucket"] = strings.ToLower(awsBucketName)
+	config["name"] = name
+	config["bucket"] = strings.ToLower(awsBucket)
 	config["defaultRegion"] = awsRegion
 	config["storageClass"] = strings.ToUpper(storageClass)
 	config["accessKey"] = awsAccessKey
 	config["secretKey"] = awsSecretKey
 	config["objectStoreType"] = "S3"
-	config["kmsMasterKeyId"] = kmsMasterKeyID
+	for key, value := range encryption {
+		config[key] = value
+	}
 
 	// Create a simplified config that only includes the values returned by Rubrik that can be used for idempotence check
 	redactedConfig := map[string]interface{}{}
-	redactedConfig["name"] = archiveName
-	redactedConfig["bucket"] = strings.ToLower(awsBucketName)
+	redactedConfig["name"] = name
+	redactedConfig["bucket"] = strings.ToLower(awsBucket)
 	redactedConfig["defaultRegion"] = awsRegion
 	redactedConfig["storageClass"] = strings.ToUpper(storageClass)
 	redactedConfig["accessKey"] = awsAccessKey
 	redactedConfig["objectStoreType"] = "S3"
 
-	archivesOnCluster := c.Get("internal", "/archive/object_store", httpTimeout).(map[string]interface{})["data"]
-
-	for _, v := range archivesOnCluster.([]interface{}) {
-		archiveDefinition := (v.(interface{}).(map[string]interface{})["definition"])
-		delete(archiveDefinition.(map[string]interface{}), "definition")
+	objectStores, err := c.commonAPI("GET", "internal", "/archive/object_store", nil, timeout)
+	if err != nil {
+		return nil, err
+	}
 
-		archivePresent := reflect.DeepEqual(redactedConfig, archiveDefinition)
+	objectStoreSummary, _ := objectStores.(map[string]interface{})
+	archivesOnCluster, ok := objectStoreSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the archive locations from the Rubrik cluster.")
+	}
 
-		if archivePresent {
-			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName)
+	for _, v := range archivesOnCluster {
+		archive, _ := v.(map[string]interface{})
+		archiveDefinition, ok := archive["definition"].(map[string]interface{})
+		if ok != true || archiveDefinition["objectStoreType"] != "S3" || archiveDefinition["name"] != name {
+			continue
 		}
 
-		if archiveDefinition.(map[string]interface{})["objectStoreType"] == "S3" && archiveDefinition.(map[string]interface{})["name"] == archiveName {
-
-			log.Fatalf(fmt.Sprintf("Error: An archive location with the name '%s' already exists. Please enter a unique 'archiveName'", archiveName))
+		currentDefinition := map[string]interface{}{}
+		for key, value := range archiveDefinition {
+			if key != "definition" {
+				currentDefinition[key] = value
+			}
+		}
 
+		if reflect.DeepEqual(redactedConfig, currentDefinition) {
+			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", name), nil
 		}
 
+		return nil, fmt.Errorf("An archive location with the name '%s' already exists. Please enter a unique 'name'.", name)
 	}
 
-	return c.Post("internal", "/archive/object_store", config, httpTimeout)
+	return c.commonAPI("POST", "internal", "/archive/object_store", config, timeout)
 }
 
 // AWSS3CloudOn provides the ability to convert a vSphere virtual machines snapshot, an archived snapshot, or a replica into an Amazon Machine Image (AMI)
@@ -296,7 +290,7 @@ func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName,
 //	- The full API response for PATCH /internal/archive/object_store.
 func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
 	config := map[string]interface{}{}
 	config["defaultComputeNetworkConfig"] = map[string]interface{}{}
@@ -340,75 +334,165 @@ func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID
 //	- The full API response for POST /internal/archive/object_store.
 func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountName, archiveName, instanceType, rsaKey string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
+
+	azureCloudOut, err := c.azureArchivalLocation(archiveName, container, storageAccountName, azureAccessKey, instanceType, AzureArchivalLocationOptions{RSAKey: rsaKey}, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return azureCloudOut
+}
+
+// AzureArchivalLocationOptions contains the optional settings used by AddAzureArchivalLocation(). "RSAKey" is the PEM encoded RSA key used
+// to encrypt the archived data. When "ProxyServer" is provided the Rubrik cluster connects to the Azure container through that proxy
+// using "ProxyProtocol" (HTTP, HTTPS, or SOCKS5) and "ProxyPort". "ProxyUsername" and "ProxyPassword" are only required for an
+// authenticated proxy.
+type AzureArchivalLocationOptions struct {
+	RSAKey        string
+	ProxyServer   string
+	ProxyProtocol string
+	ProxyPort     int
+	ProxyUsername string
+	ProxyPassword string
+}
+
+// AddAzureArchivalLocation configures a new Azure blob archive target named "name" that stores its data in the "container" of the Azure
+// "storageAccount". The "azureRegion" is the Azure cloud hosting the storage account, which determines the endpoint used by the Rubrik
+// cluster.
+//
+// Valid "azureRegion" choices are:
+//
+//	default, china, germany, and government
+//
+// Valid "ProxyProtocol" choices are:
+//
+//	HTTP, HTTPS, and SOCKS5
+//
+// The function will return one of the following:
+//	- No change required. The '{name}' archive location is already configured on the Rubrik cluster.
+//
+//	- The full API response for POST /internal/archive/object_store.
+func (c *Credentials) AddAzureArchivalLocation(name, container, storageAccount, accessKey, azureRegion string, options AzureArchivalLocationOptions, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
 
-	validInstanceTypes := map[string]bool{
-		"default":    true,
-		"china":      true,
-		"germany":    true,
-		"government": true,
+	return c.azureArchivalLocation(name, container, storageAccount, accessKey, azureRegion, options, httpTimeout)
+}
+
+// azureArchivalLocation configures a new Azure archive target unless an archive location with the same definition already exists.
+func (c *Credentials) azureArchivalLocation(name, container, storageAccount, accessKey, azureRegion string, options AzureArchivalLocationOptions, timeout int) (interface{}, error) {
+
+	azureEndpoints := map[string]string{
+		"default":    "",
+		"china":      "core.chinacloudapi.cn",
+		"germany":    "core.cloudapi.de",
+		"government": "core.usgovcloudapi.net",
 	}
 
-	if validInstanceTypes[instanceType] == false {
-		log.Fatalf(fmt.Sprintf("Error: '%s' is not a valid Azure Instance Type. Valid choices are 'default', 'china', 'germany', or 'government'", instanceType))
+	endpoint, ok := azureEndpoints[azureRegion]
+	if ok != true {
+		return nil, fmt.Errorf("'%s' is not a valid Azure region. Valid choices are 'default', 'china', 'germany', or 'government'.", azureRegion)
 	}
 
-	config := map[string]string{}
-	config["name"] = archiveName
+	validProxyProtocols := map[string]bool{
+		"HTTP":   true,
+		"HTTPS":  true,
+		"SOCKS5": true,
+	}
+
+	if len(options.ProxyServer) != 0 && validProxyProtocols[options.ProxyProtocol] == false {
+		return nil, fmt.Errorf("'%s' is not a valid proxy protocol. Valid choices are 'HTTP', 'HTTPS', or 'SOCKS5'.", options.ProxyProtocol)
+	}
+
+	config := map[string]interface{}{}
+	config["name"] = name
 	config["bucket"] = container
-	config["accessKey"] = storageAccountName
-	config["secretKey"] = azureAccessKey
+	config["accessKey"] = storageAccount
+	config["secretKey"] = accessKey
 	config["objectStoreType"] = "Azure"
-	config["pemFileContent"] = rsaKey
-	switch instanceType {
-	case "government":
-		config["endpoint"] = "core.usgovcloudapi.net"
-	case "germany":
-		config["endpoint"] = "core.cloudapi.de"
-	case "china":
-		config["endpoint"] = "core.chinacloudapi.cn"
+	if len(options.RSAKey) != 0 {
+		config["pemFileContent"] = options.RSAKey
 	}
 
 	// Create a simplified config that only includes the values returned by Rubrik that can be used for idempotence check
 	redactedConfig := map[string]interface{}{}
 	redactedConfig["objectStoreType"] = "Azure"
-	redactedConfig["name"] = archiveName
-	redactedConfig["accessKey"] = storageAccountName
+	redactedConfig["name"] = name
+	redactedConfig["accessKey"] = storageAccount
 	redactedConfig["bucket"] = container
-	switch instanceType {
-	case "government":
-		redactedConfig["endpoint"] = "core.usgovcloudapi.net"
-	case "germany":
-		redactedConfig["endpoint"] = "core.cloudapi.de"
-	case "china":
-		redactedConfig["endpoint"] = "core.chinacloudapi.cn"
+
+	if len(endpoint) != 0 {
+		config["endpoint"] = endpoint
+		redactedConfig["endpoint"] = endpoint
 	}
 
-	archivesOnCluster := c.Get("internal", "/archive/object_store", httpTimeout).(map[string]interface{})["data"]
+	if len(options.ProxyServer) != 0 {
+		proxySettings := map[string]interface{}{}
+		proxySettings["protocol"] = options.ProxyProtocol
+		proxySettings["proxyServer"] = options.ProxyServer
+		proxySettings["portNumber"] = options.ProxyPort
+		if len(options.ProxyUsername) != 0 {
+			proxySettings["userName"] = options.ProxyUsername
+			proxySettings["password"] = options.ProxyPassword
+		}
+		config["proxySettings"] = proxySettings
+	}
 
-	for _, v := range archivesOnCluster.([]interface{}) {
-		archiveDefinition := (v.(interface{}).(map[string]interface{})["definition"])
-		delete(archiveDefinition.(map[string]interface{}), "defaultComputeNetworkConfig")
-		delete(archiveDefinition.(map[string]interface{}), "isComputeEnabled")
-		delete(archiveDefinition.(map[string]interface{}), "isConsolidationEnabled")
-		delete(archiveDefinition.(map[string]interface{}), "azureComputeSummary")
+	objectStores, err := c.commonAPI("GET", "internal", "/archive/object_store", nil, timeout)
+	if err != nil {
+		return nil, err
+	}
 
-		archivePresent := reflect.DeepEqual(redactedConfig, archiveDefinition)
+	objectStoreSummary, _ := objectStores.(map[string]interface{})
+	archivesOnCluster, ok := objectStoreSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the archive locations from the Rubrik cluster.")
+	}
 
-		if archivePresent {
-			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName)
+	for _, v := range archivesOnCluster {
+		archive, _ := v.(map[string]interface{})
+		archiveDefinition, ok := archive["definition"].(map[string]interface{})
+		if ok != true || archiveDefinition["objectStoreType"] != "Azure" || archiveDefinition["name"] != name {
+			continue
 		}
 
-		if archiveDefinition.(map[string]interface{})["objectStoreType"] == "Azure" && archiveDefinition.(map[string]interface{})["name"] == archiveName {
+		// The proxy settings are compared separately since their port number is not a string
+		currentProxySettings, _ := archiveDefinition["proxySettings"].(map[string]interface{})
 
-			log.Fatalf(fmt.Sprintf("Error: An arhive location with the name '%s' already exists. Please enter a unique 'archiveName'", archiveName))
+		currentDefinition := map[string]interface{}{}
+		for key, value := range archiveDefinition {
+			switch key {
+			case "defaultComputeNetworkConfig", "isComputeEnabled", "isConsolidationEnabled", "azureComputeSummary", "proxySettings":
+			default:
+				currentDefinition[key] = value
+			}
+		}
 
+		if reflect.DeepEqual(redactedConfig, currentDefinition) && proxySettingsEqual(options, currentProxySettings) {
+			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", name), nil
 		}
 
+		return nil, fmt.Errorf("An archive location with the name '%s' already exists. Please enter a unique 'name'.", name)
+	}
+
+	return c.commonAPI("POST", "internal", "/archive/object_store", config, timeout)
+}
+
+// proxySettingsEqual determines if the "proxySettings" of an existing archive location match the proxy provided in the "options". The
+// proxy password is not returned by Rubrik so it is not compared.
+func proxySettingsEqual(options AzureArchivalLocationOptions, proxySettings map[string]interface{}) bool {
+
+	if len(options.ProxyServer) == 0 {
+		return len(proxySettings) == 0
 	}
 
-	return c.Post("internal", "/archive/object_store", config, httpTimeout)
+	userName, _ := proxySettings["userName"].(string)
 
+	return proxySettings["proxyServer"] == options.ProxyServer &&
+		proxySettings["protocol"] == options.ProxyProtocol &&
+		numberEq(proxySettings["portNumber"], options.ProxyPort) &&
+		userName == options.ProxyUsername
 }
 
 // AzureCloudOn provides the ability to convert a snapshot, archived snapshot, or replica into a Virtual Hard Disk (VHD). This enables the instantiation
@@ -425,7 +509,7 @@ func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountNam
 //	- The full API response for PATCH /internal/archive/object_store.
 func (c *Credentials) AzureCloudOn(archiveName, container, storageAccountName, applicationID, applicationKey, directoryID, region, virtualNetworkID, subnetName, securityGroupID string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
 	fmt.Println(httpTimeout)
 
@@ -527,3 +611,193 @@ func (c *Credentials) AzureCloudOn(archiveName, container, storageAccountName, a
 	return ""
 
 }
+
+// AddNFSArchivalLocation configures a new NFS archive target using the "exportDir" export of the NFS server "host".
+//
+// Valid "authType" choices are:
+//
+//	SYSTEM and KERBEROS
+//
+// The function will return one of the following:
+//	- No change required. The '{name}' archive location is already configured on the Rubrik cluster.
+//
+//	- The full API response for POST /internal/archive/nfs.
+func (c *Credentials) AddNFSArchivalLocation(name, host, exportDir, authType string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validAuthTypes := map[string]bool{
+		"SYSTEM":   true,
+		"KERBEROS": true,
+	}
+
+	if validAuthTypes[authType] == false {
+		return nil, fmt.Errorf("'%s' is not a valid 'authType'. Valid choices are 'SYSTEM' or 'KERBEROS'.", authType)
+	}
+
+	archivesOnCluster, err := c.GetArchivalLocations(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := archivesOnCluster[name]; ok {
+		return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", name), nil
+	}
+
+	config := map[string]string{}
+	config["name"] = name
+	config["host"] = host
+	config["exportDir"] = exportDir
+	config["authType"] = authType
+
+	return c.commonAPI("POST", "internal", "/archive/nfs", config, httpTimeout)
+}
+
+// GetArchivalLocations returns the name and ID of all archive locations configured on the Rubrik cluster in a {name: id} format.
+func (c *Credentials) GetArchivalLocations(timeout ...int) (map[string]string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/archive/location", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveLocationSummary, _ := apiRequest.(map[string]interface{})
+	archiveLocations, ok := archiveLocationSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the archive locations from the Rubrik cluster.")
+	}
+
+	archiveNameID := map[string]string{}
+	for _, v := range archiveLocations {
+		archiveLocation, ok := v.(map[string]interface{})
+		if ok != true {
+			continue
+		}
+
+		archiveName, _ := archiveLocation["name"].(string)
+		archiveID, _ := archiveLocation["id"].(string)
+		archiveNameID[archiveName] = archiveID
+	}
+
+	return archiveNameID, nil
+}
+
+// DeleteArchivalLocation removes the archive location "name" from the Rubrik cluster. If the archive location still contains snapshots
+// the Rubrik cluster will reject the request and an *APIError containing the Rubrik error message is returned.
+//
+// The function will return:
+//	The full API response for DELETE /internal/archive/location/{archiveID}.
+func (c *Credentials) DeleteArchivalLocation(name string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	archivesOnCluster, err := c.GetArchivalLocations(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveID, ok := archivesOnCluster[name]
+	if ok != true {
+		return nil, fmt.Errorf("The Rubrik cluster does not have an archive location named '%s'.", name)
+	}
+
+	return c.commonAPI("DELETE", "internal", fmt.Sprintf("/archive/location/%s", archiveID), nil, httpTimeout)
+}
+
+// TriggerArchiveConsolidation enables consolidation on the object store archive location "archivalLocationName" (ex: Amazon S3 or Azure)
+// so that the Rubrik cluster reclaims the storage used by expired snapshot data in the archive. The Rubrik API does not provide an
+// on-demand consolidation job, so once enabled the Rubrik cluster consolidates the archive location on its own schedule. Consolidation
+// is not supported for NFS archive locations.
+//
+// The function will return one of the following:
+//	No change required. Consolidation is already enabled on the '{archivalLocationName}' archive location.
+//
+//	The full API response for PATCH /internal/archive/object_store/{archiveID}
+func (c *Credentials) TriggerArchiveConsolidation(archivalLocationName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	archivesOnCluster, err := c.GetArchivalLocations(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveID, ok := archivesOnCluster[archivalLocationName]
+	if ok != true {
+		return nil, fmt.Errorf("The Rubrik cluster does not have an archive location named '%s'.", archivalLocationName)
+	}
+
+	objectStore, err := c.commonAPI("GET", "internal", fmt.Sprintf("/archive/object_store/%s", archiveID), nil, httpTimeout)
+	if apiError, ok := err.(*APIError); ok && apiError.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("Consolidation is only supported for object store archive locations and '%s' is not an object store.", archivalLocationName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if consolidationEnabled, _ := apiValue(objectStore, []string{"definition", "isConsolidationEnabled"}); consolidationEnabled == true {
+		return fmt.Sprintf("No change required. Consolidation is already enabled on the '%s' archive location.", archivalLocationName), nil
+	}
+
+	config := map[string]bool{}
+	config["isConsolidationEnabled"] = true
+
+	return c.commonAPI("PATCH", "internal", fmt.Sprintf("/archive/object_store/%s", archiveID), config, httpTimeout)
+}
+
+// ArchivalLocationHealth contains the connectivity of a single archive location. "LastSuccessfulUpload" is calculated from the most
+// recent successful archival event and is zero when no recent upload to the archive location was found.
+type ArchivalLocationHealth struct {
+	ID                    string
+	Name                  string
+	LocationType          string
+	Status                string
+	LastConnectionFailure time.Time
+	LastSuccessfulUpload  time.Time
+}
+
+// GetArchivalLocationHealth returns the current connection status, the time of the last connection failure, and the time of the last
+// successful upload of each archive location configured on the Rubrik cluster.
+func (c *Credentials) GetArchivalLocationHealth(timeout ...int) ([]ArchivalLocationHealth, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	locationSummary, err := c.commonAPI("GET", "internal", "/archive/location", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := getSlice(locationSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	events, _, err := c.latestEvents("event_type=Archive&limit=1000", httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	locationHealth := []ArchivalLocationHealth{}
+	for _, location := range locations {
+		health := ArchivalLocationHealth{}
+		health.ID, _ = getString(location, "id")
+		health.Name, _ = getString(location, "name")
+		health.LocationType, _ = getString(location, "locationType")
+		health.Status, _ = getString(location, "currentState")
+
+		if lastFailure, err := getString(location, "lastConnectionFailureTime"); err == nil {
+			health.LastConnectionFailure, _ = time.Parse(time.RFC3339, lastFailure)
+		}
+
+		for _, event := range events {
+			if event.EventStatus == "Success" && strings.Contains(event.Message, health.Name) && event.Time.After(health.LastSuccessfulUpload) {
+				health.LastSuccessfulUpload = event.Time
+			}
+		}
+
+		locationHealth = append(locationHealth, health)
+	}
+
+	return locationHealth, nil
+}
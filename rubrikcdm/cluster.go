@@ -1,10 +1,16 @@
 package rubrikcdm
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -25,14 +31,48 @@ func (c *Credentials) ClusterVersionCheck(clusterVersion float64) {
 	}
 }
 
+// Node contains the details of a single node in the Rubrik cluster.
+type Node struct {
+	ID        string `json:"id"`
+	IPAddress string `json:"ipAddress"`
+	Status    string `json:"status"`
+	BrikID    string `json:"brikId"`
+}
+
+// ClusterNodes returns the ID, IP address, status, and brik ID of every node in the Rubrik cluster.
+func (c *Credentials) ClusterNodes(timeout ...int) ([]Node, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	nodeSummary, err := c.commonAPI("GET", "internal", "/cluster/me/node", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeData, err := getSlice(nodeSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []Node
+	if err := convertResponse(nodeData, &nodes); err != nil {
+		return nil, fmt.Errorf("Unable to read the nodes from the Rubrik cluster: %s", err)
+	}
+
+	return nodes, nil
+}
+
 // ClusterNodeIP returns all Node IPs in the Rubrik cluster.
 func (c *Credentials) ClusterNodeIP() []string {
-	apiRequest := c.Get("internal", "/cluster/me/node").(map[string]interface{})
+	nodes, err := c.ClusterNodes()
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
 
 	var nodeList []string
 
-	for _, v := range apiRequest["data"].([]interface{}) {
-		nodeList = append(nodeList, v.(interface{}).(map[string]interface{})["ipAddress"].(string))
+	for _, node := range nodes {
+		nodeList = append(nodeList, node.IPAddress)
 	}
 
 	return nodeList
@@ -40,17 +80,538 @@ func (c *Credentials) ClusterNodeIP() []string {
 
 // ClusterNodeName returns the name of all nodes in the Rubrik cluster.
 func (c *Credentials) ClusterNodeName() []string {
-	apiRequest := c.Get("internal", "/cluster/me/node").(map[string]interface{})
+	nodes, err := c.ClusterNodes()
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
 
 	var nodeName []string
 
-	for _, v := range apiRequest["data"].([]interface{}) {
-		nodeName = append(nodeName, v.(interface{}).(map[string]interface{})["id"].(string))
+	for _, node := range nodes {
+		nodeName = append(nodeName, node.ID)
 	}
 
 	return nodeName
 }
 
+// GetClusterName returns the name of the Rubrik cluster.
+func (c *Credentials) GetClusterName(timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "v1", "/cluster/me", nil, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	clusterSummary, ok := apiRequest.(map[string]interface{})
+	if ok != true {
+		return "", errors.New("Unable to read the cluster summary from the Rubrik cluster.")
+	}
+
+	clusterName, ok := clusterSummary["name"].(string)
+	if ok != true {
+		return "", errors.New("The Rubrik cluster summary does not contain a cluster name.")
+	}
+
+	return clusterName, nil
+}
+
+// SetClusterName updates the name of the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already named '{name}'.
+//
+//	The full API response for PATCH /v1/cluster/me
+func (c *Credentials) SetClusterName(name string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(name) == 0 {
+		return nil, errors.New("The cluster 'name' must not be a blank string.")
+	}
+
+	currentName, err := c.GetClusterName(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentName == name {
+		return fmt.Sprintf("No change required. The Rubrik cluster is already named '%s'.", name), nil
+	}
+
+	config := map[string]string{}
+	config["name"] = name
+
+	return c.commonAPI("PATCH", "v1", "/cluster/me", config, httpTimeout)
+}
+
+// ClusterStorageStats contains the storage capacity and utilization, in bytes, of a Rubrik cluster.
+type ClusterStorageStats struct {
+	Total         int64 `json:"total"`
+	Used          int64 `json:"used"`
+	Available     int64 `json:"available"`
+	Snapshot      int64 `json:"snapshot"`
+	LiveMount     int64 `json:"liveMount"`
+	Miscellaneous int64 `json:"miscellaneous"`
+}
+
+// ClusterStorage returns the total, used, available, snapshot, live mount, and miscellaneous storage capacity of the Rubrik cluster.
+func (c *Credentials) ClusterStorage(timeout ...int) (*ClusterStorageStats, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/stats/system_storage", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var storage ClusterStorageStats
+	if err := convertResponse(apiRequest, &storage); err != nil {
+		return nil, fmt.Errorf("Unable to read the storage stats from the Rubrik cluster: %s", err)
+	}
+
+	return &storage, nil
+}
+
+// ClusterRunway returns the number of days remaining before the Rubrik cluster runs out of storage capacity along with the full API
+// response for GET /internal/stats/runway_remaining.
+func (c *Credentials) ClusterRunway(timeout ...int) (int, interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/stats/runway_remaining", nil, httpTimeout)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	runway, ok := apiRequest.(map[string]interface{})
+	if ok != true {
+		return 0, apiRequest, errors.New("Unable to read the runway remaining from the Rubrik cluster.")
+	}
+
+	days, err := Float64Value(runway["days"])
+	if err != nil {
+		return 0, apiRequest, errors.New("The runway remaining stats do not contain the number of days remaining.")
+	}
+
+	return int(days), apiRequest, nil
+}
+
+// GenerateSupportBundle collects the logs of every node in the Rubrik cluster into a support bundle and returns the URL the bundle
+// can be downloaded from with DownloadSupportBundle(). Generating a support bundle can take a long time, so the function checks the
+// status of the bundle every "pollInterval" (30 seconds by default) and returns an error if the bundle is not ready within
+// "maxWait" (2 hours by default). Use 0 for either value to use the default.
+func (c *Credentials) GenerateSupportBundle(pollInterval, maxWait time.Duration, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+
+	if maxWait <= 0 {
+		maxWait = 2 * time.Hour
+	}
+
+	supportBundle, err := c.commonAPI("POST", "internal", "/support/support_bundle", map[string]interface{}{}, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	requestID, err := getString(supportBundle, "id")
+	if err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		bundleStatus, err := c.commonAPI("GET", "internal", fmt.Sprintf("/support/support_bundle?id=%s", requestID), nil, httpTimeout)
+		if err != nil {
+			return "", err
+		}
+
+		status, err := getString(bundleStatus, "status")
+		if err != nil {
+			return "", err
+		}
+
+		switch status {
+		case "SUCCEEDED":
+			links, err := getSlice(bundleStatus, "links")
+			if err != nil {
+				return "", err
+			}
+
+			for _, link := range links {
+				if rel, _ := getString(link, "rel"); rel == "download" {
+					return getString(link, "href")
+				}
+			}
+
+			return "", errors.New("The support bundle status does not contain a download link.")
+		case "FAILED", "CANCELED":
+			return "", fmt.Errorf("The support bundle request '%s' did not complete successfully. The request status is '%s'.", requestID, status)
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("Timed out waiting for the support bundle request '%s' to complete.", requestID)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// DownloadSupportBundle saves the support bundle located at "url", as returned by GenerateSupportBundle(), to "filePath".
+func (c *Credentials) DownloadSupportBundle(url, filePath string, timeout ...int) error {
+
+	httpTimeout := c.longHTTPTimeout(timeout)
+
+	if len(url) == 0 {
+		return errors.New("The 'url' must not be a blank string.")
+	}
+
+	return c.download(url, filePath, httpTimeout)
+}
+
+// EncryptionStatus contains the encryption at rest state of the Rubrik cluster. "Type" is hardware, software, or none.
+type EncryptionStatus struct {
+	Type    string
+	Enabled bool
+}
+
+// GetEncryptionStatus returns the encryption at rest state of the Rubrik cluster. Software encryption can only be enabled when the
+// Rubrik cluster is bootstrapped, through the "enableEncryption" parameter of Bootstrap(), so there is no corresponding function to
+// enable encryption on an existing cluster.
+func (c *Credentials) GetEncryptionStatus(timeout ...int) (*EncryptionStatus, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	isEncrypted, err := c.commonAPI("GET", "internal", "/cluster/me/is_encrypted", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ := apiValue(isEncrypted, []string{"value"})
+	encrypted, ok := value.(bool)
+	if ok != true {
+		return nil, errors.New("Unable to determine if the Rubrik cluster is encrypted.")
+	}
+
+	if encrypted == false {
+		return &EncryptionStatus{Type: "none"}, nil
+	}
+
+	isHardwareEncrypted, err := c.commonAPI("GET", "internal", "/cluster/me/is_hardware_encrypted", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if hardwareEncrypted, _ := apiValue(isHardwareEncrypted, []string{"value"}); hardwareEncrypted == true {
+		return &EncryptionStatus{Type: "hardware", Enabled: true}, nil
+	}
+
+	return &EncryptionStatus{Type: "software", Enabled: true}, nil
+}
+
+// HardwareComponent contains the health of a single disk, fan, power supply, or temperature sensor of a Rubrik node.
+type HardwareComponent struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// NodeHardwareStatus contains the hardware health of a single Rubrik node. "Healthy" is only true when the node and every one of its
+// components report an OK or ACTIVE status.
+type NodeHardwareStatus struct {
+	NodeID        string
+	IPAddress     string
+	Status        string
+	Healthy       bool
+	Disks         []HardwareComponent
+	Fans          []HardwareComponent
+	PowerSupplies []HardwareComponent
+	Temperatures  []HardwareComponent
+}
+
+// GetNodeHardwareStatus returns the disk, fan, power supply, and temperature health of each node in the Rubrik cluster keyed by the
+// node ID.
+func (c *Credentials) GetNodeHardwareStatus(timeout ...int) (map[string]*NodeHardwareStatus, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	nodes, err := c.ClusterNodes(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	hardwareStatus := map[string]*NodeHardwareStatus{}
+	for _, node := range nodes {
+		nodeID := node.ID
+
+		status := &NodeHardwareStatus{NodeID: nodeID, IPAddress: node.IPAddress, Status: node.Status}
+
+		nodeHealth, err := c.commonAPI("GET", "internal", fmt.Sprintf("/node/%s/hardware_health", nodeID), nil, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		var health struct {
+			Fans          []HardwareComponent `json:"fans"`
+			PowerSupplies []HardwareComponent `json:"powerSupplies"`
+			Temperatures  []HardwareComponent `json:"temperatures"`
+		}
+		if err := convertResponse(nodeHealth, &health); err != nil {
+			return nil, fmt.Errorf("Unable to read the hardware health of the node '%s': %s", nodeID, err)
+		}
+
+		status.Fans = health.Fans
+		status.PowerSupplies = health.PowerSupplies
+		status.Temperatures = health.Temperatures
+
+		hardwareStatus[nodeID] = status
+	}
+
+	disks, err := c.clusterDisks(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, disk := range disks {
+		if status, ok := hardwareStatus[disk.NodeID]; ok {
+			status.Disks = append(status.Disks, HardwareComponent{ID: disk.ID, Status: disk.Status})
+		}
+	}
+
+	for _, status := range hardwareStatus {
+		status.Healthy = hardwareHealthy(status.Status)
+		for _, components := range [][]HardwareComponent{status.Disks, status.Fans, status.PowerSupplies, status.Temperatures} {
+			for _, component := range components {
+				if hardwareHealthy(component.Status) == false {
+					status.Healthy = false
+				}
+			}
+		}
+	}
+
+	return hardwareStatus, nil
+}
+
+// hardwareHealthy determines if a node or hardware component "status" reported by the Rubrik cluster is healthy.
+func hardwareHealthy(status string) bool {
+	return strings.EqualFold(status, "OK") || strings.EqualFold(status, "ACTIVE")
+}
+
+// ClusterDisk contains the details of a single disk in a Rubrik cluster. The capacity and usage are in bytes.
+type ClusterDisk struct {
+	ID            string `json:"id"`
+	NodeID        string `json:"nodeId"`
+	Path          string `json:"path"`
+	DiskType      string `json:"diskType"`
+	Status        string `json:"status"`
+	CapacityBytes int64  `json:"capacityBytes"`
+	UsedBytes     int64  `json:"usedBytes"`
+}
+
+// NodeStorageStats contains the storage capacity and usage, in bytes, of a single Rubrik node and each of its disks.
+type NodeStorageStats struct {
+	NodeID         string
+	CapacityBytes  int64
+	UsedBytes      int64
+	AvailableBytes int64
+	Disks          []ClusterDisk
+}
+
+// GetNodeStorageStats returns the storage capacity and usage of each node in the Rubrik cluster, along with the capacity, usage, and
+// health status of every disk in the node. The node totals are the sum of the node's disks.
+func (c *Credentials) GetNodeStorageStats(timeout ...int) ([]NodeStorageStats, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	disks, err := c.clusterDisks(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeStats := []NodeStorageStats{}
+	nodeIndex := map[string]int{}
+	for _, disk := range disks {
+		i, ok := nodeIndex[disk.NodeID]
+		if ok != true {
+			nodeStats = append(nodeStats, NodeStorageStats{NodeID: disk.NodeID})
+			i = len(nodeStats) - 1
+			nodeIndex[disk.NodeID] = i
+		}
+
+		nodeStats[i].CapacityBytes += disk.CapacityBytes
+		nodeStats[i].UsedBytes += disk.UsedBytes
+		nodeStats[i].AvailableBytes = nodeStats[i].CapacityBytes - nodeStats[i].UsedBytes
+		nodeStats[i].Disks = append(nodeStats[i].Disks, disk)
+	}
+
+	sort.Slice(nodeStats, func(i, j int) bool { return nodeStats[i].NodeID < nodeStats[j].NodeID })
+
+	return nodeStats, nil
+}
+
+// clusterDisks returns every disk in the Rubrik cluster.
+func (c *Credentials) clusterDisks(timeout int) ([]ClusterDisk, error) {
+
+	diskSummary, err := c.commonAPI("GET", "internal", "/cluster/me/disk", nil, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	disksData, err := getSlice(diskSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []ClusterDisk
+	if err := convertResponse(disksData, &disks); err != nil {
+		return nil, fmt.Errorf("Unable to read the disks from the Rubrik cluster: %s", err)
+	}
+
+	return disks, nil
+}
+
+// ConnectivityCheck contains the result of a single network diagnostic run by the Rubrik cluster.
+type ConnectivityCheck struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// ClusterConnectivity contains the result of each network diagnostic run by TestClusterConnectivity(). "Healthy" is only true when
+// every check passed.
+type ClusterConnectivity struct {
+	Healthy bool
+	Checks  []ConnectivityCheck
+}
+
+// TestClusterConnectivity runs the DNS resolution, NTP reachability, and default gateway diagnostics of the Rubrik cluster and returns
+// the result of each check. A check the Rubrik cluster rejects is reported as failed with the error message of the API call.
+func (c *Credentials) TestClusterConnectivity(timeout ...int) (*ClusterConnectivity, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	diagnostics := []struct {
+		name     string
+		endpoint string
+	}{
+		{"DNS resolution", "/node_management/network_diagnostic/dns"},
+		{"NTP reachability", "/node_management/network_diagnostic/ntp"},
+		{"Gateway ping", "/node_management/network_diagnostic/gateway"},
+	}
+
+	connectivity := &ClusterConnectivity{Healthy: true}
+	for _, diagnostic := range diagnostics {
+		check := ConnectivityCheck{Name: diagnostic.name}
+
+		result, err := c.commonAPI("POST", "internal", diagnostic.endpoint, nil, httpTimeout)
+		if err != nil {
+			if _, ok := err.(*APIError); ok != true {
+				return nil, err
+			}
+			check.Message = err.Error()
+		} else {
+			passed, _ := apiValue(result, []string{"isSuccessful"})
+			check.Passed = passed == true
+			check.Message, _ = getString(result, "message")
+		}
+
+		if check.Passed == false {
+			connectivity.Healthy = false
+		}
+
+		connectivity.Checks = append(connectivity.Checks, check)
+	}
+
+	return connectivity, nil
+}
+
+// SupportTunnel contains the current state of the Rubrik support tunnel.
+type SupportTunnel struct {
+	IsTunnelEnabled            bool   `json:"isTunnelEnabled"`
+	Port                       int    `json:"port"`
+	EnabledTime                string `json:"enabledTime"`
+	LastActivityTime           string `json:"lastActivityTime"`
+	InactivityTimeoutInSeconds int    `json:"inactivityTimeoutInSeconds"`
+}
+
+// SupportTunnelStatus returns the current state of the Rubrik support tunnel including the port and inactivity timeout.
+func (c *Credentials) SupportTunnelStatus(timeout ...int) (*SupportTunnel, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/node_management/support_tunnel", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var supportTunnel SupportTunnel
+	if err := convertResponse(apiRequest, &supportTunnel); err != nil {
+		return nil, fmt.Errorf("Unable to read the support tunnel status from the Rubrik cluster: %s", err)
+	}
+
+	return &supportTunnel, nil
+}
+
+// OpenSupportTunnel enables the Rubrik support tunnel. The tunnel will automatically be closed after "inactivityTimeout" seconds without
+// activity. Use 0 for the "inactivityTimeout" to keep the tunnel open until CloseSupportTunnel() is called.
+//
+// The function will return one of the following:
+//	No change required. The support tunnel is already open.
+//
+//	The full API response for PATCH /internal/node_management/support_tunnel
+func (c *Credentials) OpenSupportTunnel(inactivityTimeout int, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if inactivityTimeout < 0 {
+		return nil, errors.New("The 'inactivityTimeout' must be 0 or greater.")
+	}
+
+	supportTunnel, err := c.SupportTunnelStatus(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if supportTunnel.IsTunnelEnabled {
+		return "No change required. The support tunnel is already open.", nil
+	}
+
+	config := map[string]interface{}{}
+	config["isTunnelEnabled"] = true
+	config["inactivityTimeoutInSeconds"] = inactivityTimeout
+
+	return c.commonAPI("PATCH", "internal", "/node_management/support_tunnel", config, httpTimeout)
+}
+
+// CloseSupportTunnel disables the Rubrik support tunnel.
+//
+// The function will return one of the following:
+//	No change required. The support tunnel is already closed.
+//
+//	The full API response for PATCH /internal/node_management/support_tunnel
+func (c *Credentials) CloseSupportTunnel(timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	supportTunnel, err := c.SupportTunnelStatus(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if supportTunnel.IsTunnelEnabled == false {
+		return "No change required. The support tunnel is already closed.", nil
+	}
+
+	config := map[string]bool{}
+	config["isTunnelEnabled"] = false
+
+	return c.commonAPI("PATCH", "internal", "/node_management/support_tunnel", config, httpTimeout)
+}
+
 // EndUserAuthorization assigns an End User account privileges for a VMware virtual machine. vmware is currently the only
 // supported "objectType"
 //
@@ -60,7 +621,7 @@ func (c *Credentials) ClusterNodeName() []string {
 //	The full API response for POST /internal/authorization/role/end_user
 func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType string, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
 	validObjectType := map[string]bool{
 		"vmware": true,
@@ -70,9 +631,12 @@ func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType strin
 		log.Fatalf("Error: The 'objectType' must be 'vmware'.")
 	}
 
-	vmID := c.ObjectID(objectName, objectType)
+	vmID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
 
-	userLookup := c.Get("internal", fmt.Sprintf("/user?username=%s", endUser)).([]interface{})
+	userLookup := c.Get("internal", queryEndpoint("/user", url.Values{"username": {endUser}})).([]interface{})
 
 	if len(userLookup) == 0 {
 		log.Fatalf(fmt.Sprintf("Error: The Rubrik cluster does not contain a End User account named '%s'.", endUser))
@@ -102,38 +666,362 @@ func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType strin
 
 }
 
-// ConfigureTimezone provides the ability to set the time zone that is used by the Rubrik cluster which uses the specified
-// time zone for time values in the web UI, all reports, SLA Domain settings, and all other time related operations.
-//
-// Valid timezone choices are:
-//
-// 	America/Anchorage, America/Araguaina, America/Barbados, America/Chicago, America/Denver, America/Los_Angeles America/Mexico_City, America/New_York,
-//	America/Noronha, America/Phoenix, America/Toronto, America/Vancouver, Asia/Bangkok, Asia/Dhaka, Asia/Dubai, Asia/Hong_Kong, Asia/Karachi, Asia/Kathmandu,
-//	Asia/Kolkata, Asia/Magadan, Asia/Singapore, Asia/Tokyo, Atlantic/Cape_Verde, Australia/Perth, Australia/Sydney, Europe/Amsterdam, Europe/Athens,
-//	Europe/London, Europe/Moscow, Pacific/Auckland, Pacific/Honolulu, Pacific/Midway, or UTC.
+// userID returns the ID of the local user matching the provided "username" or a blank string if no match is found.
+func (c *Credentials) userID(username string, timeout int) (string, error) {
+
+	apiRequest, err := c.commonAPI("GET", "internal", queryEndpoint("/user", url.Values{"username": {username}}), nil, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	userLookup, ok := apiRequest.([]interface{})
+	if ok != true {
+		return "", errors.New("Unable to read the users from the Rubrik cluster.")
+	}
+
+	for _, v := range userLookup {
+		user, ok := v.(map[string]interface{})
+		if ok != true {
+			continue
+		}
+
+		if user["username"] == username {
+			userID, _ := user["id"].(string)
+			return userID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// CreateUser creates a new local user on the Rubrik cluster. If a "role" is provided the user will be authorized for that role once
+// created. "admin" is currently the only supported "role". Use a blank string to create the user without any authorization.
 //
 // The function will return one of the following:
-//	No change required. The Rubrik cluster is already configured with '{timezone}' as it's timezone.
+//	No change required. The user '{username}' already exists on the Rubrik cluster.
 //
-//	The full API response for POST /v1/cluster/me
-func (c *Credentials) ConfigureTimezone(timezone string, timeout ...int) interface{} {
+//	The full API response for POST /internal/user
+func (c *Credentials) CreateUser(username, password, role string, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	validObjectType := map[string]bool{
-		"America/Anchorage":   true,
-		"America/Araguaina":   true,
-		"America/Barbados":    true,
-		"America/Chicago":     true,
-		"America/Denver":      true,
-		"America/Los_Angeles": true,
-		"America/Mexico_City": true,
-		"America/New_York":    true,
-		"America/Noronha":     true,
-		"America/Phoenix":     true,
-		"America/Toronto":     true,
-		"America/Vancouver":   true,
-		"Asia/Bangkok":        true,
+	if len(username) == 0 || len(password) == 0 {
+		return nil, errors.New("The 'username' and 'password' must not be blank strings.")
+	}
+
+	validRole := map[string]bool{
+		"":      true,
+		"admin": true,
+	}
+
+	if validRole[role] == false {
+		return nil, errors.New("The 'role' must be 'admin' or a blank string.")
+	}
+
+	currentUserID, err := c.userID(username, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(currentUserID) != 0 {
+		return fmt.Sprintf("No change required. The user '%s' already exists on the Rubrik cluster.", username), nil
+	}
+
+	config := map[string]string{}
+	config["username"] = username
+	config["password"] = password
+
+	createUser, err := c.commonAPI("POST", "internal", "/user", config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(role) != 0 {
+		if _, err := c.AssignUserRole(username, role, "", nil, httpTimeout); err != nil {
+			return nil, fmt.Errorf("The user '%s' was created but could not be assigned the '%s' role: %s", username, role, err)
+		}
+	}
+
+	return createUser, nil
+}
+
+// AssignUserRole authorizes the local user "username" for the provided "role" over the "objects". When "objectType" is a blank string
+// each entry in "objects" must be a Rubrik object or SLA Domain ID. Otherwise each entry is the name of an object of that type and
+// is resolved to its ID (ex: use "sla" to scope the role to SLA Domains by name). The "admin" role is always granted over the
+// entire Rubrik cluster so "objects" must be empty when using it.
+//
+// Valid "role" choices are:
+//
+//	admin, end_user
+//
+// Valid "objectType" choices are:
+//
+//	vmware, sla, physicalHost, fileset, managedVolume, mssql, and oracleDB
+//
+// The function will return one of the following:
+//	No change required. The user '{username}' is already assigned the '{role}' role.
+//
+//	The full API response for POST /internal/authorization/role/{role}
+func (c *Credentials) AssignUserRole(username, role, objectType string, objects []string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	// Map each role to the privilege that will be granted
+	validRole := map[string]string{
+		"admin":    "fullAdmin",
+		"end_user": "restore",
+	}
+
+	privilege, ok := validRole[role]
+	if ok != true {
+		return nil, errors.New("The 'role' must be 'admin' or 'end_user'.")
+	}
+
+	if role == "admin" && len(objects) != 0 {
+		return nil, errors.New("The 'admin' role must not be scoped to specific 'objects'.")
+	}
+
+	if role == "end_user" && len(objects) == 0 {
+		return nil, errors.New("The 'end_user' role requires at least one object in 'objects'.")
+	}
+
+	validObjectType := map[string]bool{
+		"":              true,
+		"vmware":        true,
+		"sla":           true,
+		"physicalHost":  true,
+		"fileset":       true,
+		"managedVolume": true,
+		"mssql":         true,
+		"oracleDB":      true,
+	}
+
+	if validObjectType[objectType] == false {
+		return nil, errors.New("The 'objectType' must be a blank string, 'vmware', 'sla', 'physicalHost', 'fileset', 'managedVolume', 'mssql', or 'oracleDB'.")
+	}
+
+	currentUserID, err := c.userID(username, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(currentUserID) == 0 {
+		return nil, fmt.Errorf("The Rubrik cluster does not contain a user named '%s'.", username)
+	}
+
+	requestedObjects := []string{}
+	if role == "admin" {
+		requestedObjects = append(requestedObjects, "Global:::All")
+	}
+	for _, object := range objects {
+		if len(objectType) != 0 {
+			object, err = c.ObjectID(object, objectType)
+			if err != nil {
+				return nil, err
+			}
+		}
+		requestedObjects = append(requestedObjects, object)
+	}
+
+	apiRequest, err := c.commonAPI("GET", "internal", fmt.Sprintf("/authorization/role/%s?principals=%s", role, currentUserID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedObjects := map[string]bool{}
+	userAuthorization, _ := apiRequest.(map[string]interface{})
+	authorizationData, _ := userAuthorization["data"].([]interface{})
+	for _, v := range authorizationData {
+		authorization, _ := v.(map[string]interface{})
+		privileges, _ := authorization["privileges"].(map[string]interface{})
+		currentObjects, _ := privileges[privilege].([]interface{})
+		for _, object := range currentObjects {
+			if objectID, ok := object.(string); ok {
+				authorizedObjects[objectID] = true
+			}
+		}
+	}
+
+	newObjects := []string{}
+	for _, objectID := range requestedObjects {
+		if authorizedObjects[objectID] == false {
+			newObjects = append(newObjects, objectID)
+		}
+	}
+
+	if len(newObjects) == 0 {
+		return fmt.Sprintf("No change required. The user '%s' is already assigned the '%s' role.", username, role), nil
+	}
+
+	config := map[string]interface{}{}
+	config["principals"] = []string{currentUserID}
+	config["privileges"] = map[string][]string{
+		privilege: newObjects,
+	}
+
+	return c.commonAPI("POST", "internal", fmt.Sprintf("/authorization/role/%s", role), config, httpTimeout)
+}
+
+// DeleteUser deletes the local user matching the provided "username" from the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The user '{username}' is not present on the Rubrik cluster.
+//
+//	The full API response for DELETE /internal/user/{id}
+func (c *Credentials) DeleteUser(username string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	currentUserID, err := c.userID(username, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(currentUserID) == 0 {
+		return fmt.Sprintf("No change required. The user '%s' is not present on the Rubrik cluster.", username), nil
+	}
+
+	return c.commonAPI("DELETE", "internal", fmt.Sprintf("/user/%s", currentUserID), nil, httpTimeout)
+}
+
+// GenerateAPIToken creates a new API token for the authenticated user that will expire after "expirationMinutes". The "tag" is used
+// to identify the token in the Rubrik UI. The new token is stored in the Credentials and used in place of the username and password
+// for all subsequent API calls.
+func (c *Credentials) GenerateAPIToken(expirationMinutes int, tag string, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if expirationMinutes <= 0 {
+		return "", errors.New("The 'expirationMinutes' must be greater than 0.")
+	}
+
+	config := map[string]interface{}{}
+	config["initParams"] = map[string]interface{}{
+		"apiToken": map[string]interface{}{
+			"expiration": expirationMinutes,
+			"tag":        tag,
+		},
+	}
+
+	apiRequest, err := c.commonAPI("POST", "v1", "/session", config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	session, _ := apiRequest.(map[string]interface{})
+	token, ok := session["token"].(string)
+	if ok != true || len(token) == 0 {
+		return "", errors.New("The Rubrik cluster did not return an API token.")
+	}
+
+	c.APIToken = token
+
+	return token, nil
+}
+
+// RevokeAPIToken revokes the API token with the provided "tokenID". Use "me" as the "tokenID" to revoke the API token currently
+// stored in the Credentials, after which the username and password will be used for any subsequent API calls.
+//
+// The function will return the full API response for DELETE /v1/session/{tokenID}
+func (c *Credentials) RevokeAPIToken(tokenID string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(tokenID) == 0 {
+		return nil, errors.New("The 'tokenID' must not be a blank string.")
+	}
+
+	apiRequest, err := c.commonAPI("DELETE", "v1", fmt.Sprintf("/session/%s", tokenID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenID == "me" {
+		c.APIToken = ""
+	}
+
+	return apiRequest, nil
+}
+
+// ConfigureTimezone provides the ability to set the time zone that is used by the Rubrik cluster which uses the specified
+// time zone for time values in the web UI, all reports, SLA Domain settings, and all other time related operations. Use
+// SetClusterTimezone() to have any error returned instead of exiting.
+//
+// Valid timezone choices are:
+//
+// 	America/Anchorage, America/Araguaina, America/Barbados, America/Chicago, America/Denver, America/Los_Angeles America/Mexico_City, America/New_York,
+//	America/Noronha, America/Phoenix, America/Toronto, America/Vancouver, Asia/Bangkok, Asia/Dhaka, Asia/Dubai, Asia/Hong_Kong, Asia/Karachi, Asia/Kathmandu,
+//	Asia/Kolkata, Asia/Magadan, Asia/Singapore, Asia/Tokyo, Atlantic/Cape_Verde, Australia/Perth, Australia/Sydney, Europe/Amsterdam, Europe/Athens,
+//	Europe/London, Europe/Moscow, Pacific/Auckland, Pacific/Honolulu, Pacific/Midway, or UTC.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with '{timezone}' as it's timezone.
+//
+//	The full API response for PATCH /v1/cluster/me
+func (c *Credentials) ConfigureTimezone(timezone string, timeout ...int) interface{} {
+
+	configureTimezone, err := c.SetClusterTimezone(timezone, timeout...)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return configureTimezone
+
+}
+
+// GetClusterTimezone returns the time zone currently used by the Rubrik cluster (ex: America/Chicago). Use SetClusterTimezone() to
+// change the time zone.
+func (c *Credentials) GetClusterTimezone(timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "v1", "/cluster/me", nil, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	clusterSummary, _ := apiRequest.(map[string]interface{})
+	timezone, _ := clusterSummary["timezone"].(map[string]interface{})
+	clusterTimezone, ok := timezone["timezone"].(string)
+	if ok != true {
+		return "", errors.New("Unable to read the time zone from the Rubrik cluster.")
+	}
+
+	return clusterTimezone, nil
+}
+
+// SetClusterTimezone sets the time zone that is used by the Rubrik cluster for time values in the web UI, all reports, SLA Domain
+// settings, and all other time related operations.
+//
+// Valid timezone choices are:
+//
+// 	America/Anchorage, America/Araguaina, America/Barbados, America/Chicago, America/Denver, America/Los_Angeles America/Mexico_City, America/New_York,
+//	America/Noronha, America/Phoenix, America/Toronto, America/Vancouver, Asia/Bangkok, Asia/Dhaka, Asia/Dubai, Asia/Hong_Kong, Asia/Karachi, Asia/Kathmandu,
+//	Asia/Kolkata, Asia/Magadan, Asia/Singapore, Asia/Tokyo, Atlantic/Cape_Verde, Australia/Perth, Australia/Sydney, Europe/Amsterdam, Europe/Athens,
+//	Europe/London, Europe/Moscow, Pacific/Auckland, Pacific/Honolulu, Pacific/Midway, or UTC.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with '{timezone}' as it's timezone.
+//
+//	The full API response for PATCH /v1/cluster/me
+func (c *Credentials) SetClusterTimezone(timezone string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validTimezone := map[string]bool{
+		"America/Anchorage":   true,
+		"America/Araguaina":   true,
+		"America/Barbados":    true,
+		"America/Chicago":     true,
+		"America/Denver":      true,
+		"America/Los_Angeles": true,
+		"America/Mexico_City": true,
+		"America/New_York":    true,
+		"America/Noronha":     true,
+		"America/Phoenix":     true,
+		"America/Toronto":     true,
+		"America/Vancouver":   true,
+		"Asia/Bangkok":        true,
 		"Asia/Dhaka":          true,
 		"Asia/Dubai":          true,
 		"Asia/Hong_Kong":      true,
@@ -156,318 +1044,1240 @@ func (c *Credentials) ConfigureTimezone(timezone string, timeout ...int) interfa
 		"UTC":                 true,
 	}
 
-	if validObjectType[timezone] == false {
-		log.Fatalf("Error: The 'timezone' must be 'America/Anchorage', 'America/Araguaina', 'America/Barbados', 'America/Chicago', 'America/Denver', 'America/Los_Angeles' 'America/Mexico_City', 'America/New_York', 'America/Noronha', 'America/Phoenix', 'America/Toronto', 'America/Vancouver', 'Asia/Bangkok', 'Asia/Dhaka', 'Asia/Dubai', 'Asia/Hong_Kong', 'Asia/Karachi', 'Asia/Kathmandu', 'Asia/Kolkata', 'Asia/Magadan', 'Asia/Singapore', 'Asia/Tokyo', 'Atlantic/Cape_Verde', 'Australia/Perth', 'Australia/Sydney', 'Europe/Amsterdam', 'Europe/Athens', 'Europe/London', 'Europe/Moscow', 'Pacific/Auckland', 'Pacific/Honolulu', 'Pacific/Midway', or 'UTC'.")
+	if validTimezone[timezone] == false {
+		return nil, errors.New("The 'timezone' must be 'America/Anchorage', 'America/Araguaina', 'America/Barbados', 'America/Chicago', 'America/Denver', 'America/Los_Angeles' 'America/Mexico_City', 'America/New_York', 'America/Noronha', 'America/Phoenix', 'America/Toronto', 'America/Vancouver', 'Asia/Bangkok', 'Asia/Dhaka', 'Asia/Dubai', 'Asia/Hong_Kong', 'Asia/Karachi', 'Asia/Kathmandu', 'Asia/Kolkata', 'Asia/Magadan', 'Asia/Singapore', 'Asia/Tokyo', 'Atlantic/Cape_Verde', 'Australia/Perth', 'Australia/Sydney', 'Europe/Amsterdam', 'Europe/Athens', 'Europe/London', 'Europe/Moscow', 'Pacific/Auckland', 'Pacific/Honolulu', 'Pacific/Midway', or 'UTC'.")
+	}
+
+	clusterTimezone, err := c.GetClusterTimezone(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if clusterTimezone == timezone {
+		return fmt.Sprintf("No change required. The Rubrik cluster is already configured with '%s' as it's timezone.", timezone), nil
+	}
+
+	config := map[string]interface{}{}
+	config["timezone"] = map[string]string{
+		"timezone": timezone,
+	}
+
+	return c.commonAPI("PATCH", "v1", "/cluster/me", config, httpTimeout)
+}
+
+// ConfigureNTP provides the connection information for the NTP servers used for time synchronization.
+//
+// The function will return one of the following:
+//	No change required. The NTP server(s) {ntpServers} has already been added to the Rubrik cluster.
+//
+//	The full API response for POST /internal/cluster/me/ntp_server
+func (c *Credentials) ConfigureNTP(ntpServers []string, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	clusterNTP := c.Get("internal", "/cluster/me/ntp_server").(map[string]interface{})["data"]
+
+	// Convert the clusterNTP Slice to []string
+	var currentNTPServers = []string{}
+	for _, server := range clusterNTP.([]interface{}) {
+		currentNTPServers = append(currentNTPServers, server.(string))
+
+	}
+
+	updateNTP := false
+	if len(ntpServers) == len(currentNTPServers) {
+		for i := range ntpServers {
+			if ntpServers[i] != currentNTPServers[i] {
+				updateNTP = true
+			}
+		}
+	} else {
+		updateNTP = true
+	}
+
+	if updateNTP {
+		return c.Post("internal", "/cluster/me/ntp_server", ntpServers, httpTimeout)
+	}
+
+	return fmt.Sprintf("No change required. The NTP server(s) %s has already been added to the Rubrik cluster.", ntpServers)
+
+}
+
+// ConfigureSyslog enables the Rubrik cluster to send syslog server messages that are based on the events that also appear in
+// the Activity Log to the provided Syslog Server.
+//
+// Valid protocol choices are:
+//
+//	UDP, TCP
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured to use the syslog server '{syslogIP}' on port '{port}' using the '{protocol}' protocol.
+//
+//	The full API response for POST /internal/syslog
+func (c *Credentials) ConfigureSyslog(syslogIP, protocol string, port float64, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validProtocols := map[string]bool{
+		"UDP": true,
+		"TCP": true,
+	}
+
+	if validProtocols[protocol] == false {
+		log.Fatalf("Error: The 'protocol' must be 'UDP' or 'TCP'.")
+	}
+
+	config := map[string]interface{}{}
+	config["hostname"] = syslogIP
+	config["protocol"] = protocol
+	config["port"] = port
+
+	clusterSyslog := c.Get("internal", "/syslog").(map[string]interface{})["data"]
+
+	if len(clusterSyslog.([]interface{})) > 0 {
+
+		activeSyslog := clusterSyslog.([]interface{})[0]
+		// Remove id for comparison
+		delete(activeSyslog.(map[string]interface{}), "id")
+
+		deleteSyslog := false
+		if len(config) == len(activeSyslog.(map[string]interface{})) {
+			for i := range config {
+				if config[i] != activeSyslog.(map[string]interface{})[i] {
+					deleteSyslog = true
+
+				}
+			}
+
+		}
+		if deleteSyslog {
+			c.Delete("internal", "/syslog/1")
+		} else {
+			return fmt.Sprintf("No change required. The Rubrik cluster is already configured to use the syslog server '%s' on port '%d' using the '%s' protocol.", syslogIP, int(port), protocol)
+		}
+
+	}
+	return c.Post("internal", "/syslog", config, httpTimeout)
+
+}
+
+// SNMPTrapReceiver contains the address and port of a server that receives SNMP traps from the Rubrik cluster.
+type SNMPTrapReceiver struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+}
+
+// SNMPSettings contains the SNMP configuration of the Rubrik cluster.
+type SNMPSettings struct {
+	IsEnabled       bool               `json:"isEnabled"`
+	CommunityString string             `json:"communityString"`
+	TrapReceivers   []SNMPTrapReceiver `json:"trapReceiverConfigs"`
+}
+
+// GetSNMPSettings returns the SNMP configuration of the Rubrik cluster.
+func (c *Credentials) GetSNMPSettings(timeout ...int) (*SNMPSettings, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/cluster/me/snmp_configuration", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var snmpSettings SNMPSettings
+	if err := convertResponse(apiRequest, &snmpSettings); err != nil {
+		return nil, fmt.Errorf("Unable to read the SNMP configuration from the Rubrik cluster: %s", err)
+	}
+
+	return &snmpSettings, nil
+}
+
+// ConfigureSNMP enables SNMP on the Rubrik cluster with the provided "communityString" and sends SNMP traps to each of the
+// "trapReceivers". Each trap receiver should be in the following format:
+//
+//	trapReceiver := map[string]interface{}{}
+//	trapReceiver["address"] = "snmp.rubrikgo.local"
+//	trapReceiver["port"] = 162
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with the provided SNMP settings.
+//
+//	The full API response for PATCH /internal/cluster/me/snmp_configuration
+func (c *Credentials) ConfigureSNMP(communityString string, trapReceivers []map[string]interface{}, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(communityString) == 0 {
+		return nil, errors.New("The 'communityString' must not be a blank string.")
+	}
+
+	var receivers []SNMPTrapReceiver
+	if err := convertResponse(trapReceivers, &receivers); err != nil {
+		return nil, errors.New("Each trap receiver must contain an 'address' string and a 'port' number.")
+	}
+
+	for _, receiver := range receivers {
+		if len(receiver.Address) == 0 || receiver.Port <= 0 || receiver.Port > 65535 {
+			return nil, errors.New("Each trap receiver must contain an 'address' and a 'port' between 1 and 65535.")
+		}
+	}
+
+	currentSettings, err := c.GetSNMPSettings(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentSettings.IsEnabled && currentSettings.CommunityString == communityString && trapReceiversEqual(currentSettings.TrapReceivers, receivers) {
+		return "No change required. The Rubrik cluster is already configured with the provided SNMP settings.", nil
+	}
+
+	config := map[string]interface{}{}
+	config["isEnabled"] = true
+	config["communityString"] = communityString
+	config["trapReceiverConfigs"] = append([]SNMPTrapReceiver{}, receivers...)
+
+	return c.commonAPI("PATCH", "internal", "/cluster/me/snmp_configuration", config, httpTimeout)
+}
+
+// trapReceiversEqual determines if two lists of SNMP trap receivers contain the same receivers in any order.
+func trapReceiversEqual(current, desired []SNMPTrapReceiver) bool {
+
+	if len(current) != len(desired) {
+		return false
+	}
+
+	receivers := map[SNMPTrapReceiver]int{}
+	for _, receiver := range current {
+		receivers[receiver]++
+	}
+
+	for _, receiver := range desired {
+		if receivers[receiver] == 0 {
+			return false
+		}
+		receivers[receiver]--
+	}
+
+	return true
+}
+
+// certificateID returns the ID of the certificate "name" or a blank string if the certificate has not been added to the Rubrik cluster.
+func (c *Credentials) certificateID(name string, timeout int) (string, error) {
+
+	certificateSummary, err := c.commonAPI("GET", "internal", queryEndpoint("/certificate", url.Values{"name": {name}}), nil, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	certificates, err := getSlice(certificateSummary, "data")
+	if err != nil {
+		return "", err
+	}
+
+	for _, certificate := range certificates {
+		if certificateName, _ := getString(certificate, "name"); certificateName == name {
+			return getString(certificate, "certId")
+		}
+	}
+
+	return "", nil
+}
+
+// AddCertificate uploads the PEM encoded "certificate" and its "privateKey" to the Rubrik cluster as "name". Use SetWebCertificate()
+// to serve the certificate from the Rubrik cluster web UI and API.
+//
+// The function will return one of the following:
+//	No change required. The certificate '{name}' has already been added to the Rubrik cluster.
+//
+//	The full API response for POST /internal/certificate
+func (c *Credentials) AddCertificate(name, certificate, privateKey string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if strings.Contains(certificate, "-----BEGIN CERTIFICATE-----") == false {
+		return nil, errors.New("The 'certificate' must be PEM encoded.")
+	}
+
+	if strings.Contains(privateKey, "PRIVATE KEY-----") == false {
+		return nil, errors.New("The 'privateKey' must be PEM encoded.")
+	}
+
+	certificateID, err := c.certificateID(name, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(certificateID) != 0 {
+		return fmt.Sprintf("No change required. The certificate '%s' has already been added to the Rubrik cluster.", name), nil
+	}
+
+	config := map[string]string{}
+	config["name"] = name
+	config["pemFile"] = certificate
+	config["privateKey"] = privateKey
+
+	return c.commonAPI("POST", "internal", "/certificate", config, httpTimeout)
+}
+
+// SetWebCertificate configures the Rubrik cluster web UI and API to use the certificate "certName", which must already have been
+// added with AddCertificate().
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already using the '{certName}' web certificate.
+//
+//	The full API response for PUT /internal/cluster/me/web_signed_cert
+func (c *Credentials) SetWebCertificate(certName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	certificateID, err := c.certificateID(certName, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(certificateID) == 0 {
+		return nil, fmt.Errorf("The certificate '%s' was not found on the Rubrik cluster.", certName)
+	}
+
+	webCertificate, err := c.commonAPI("GET", "internal", "/cluster/me/web_signed_cert", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentCertificateID, _ := getString(webCertificate, "certId"); currentCertificateID == certificateID {
+		return fmt.Sprintf("No change required. The Rubrik cluster is already using the '%s' web certificate.", certName), nil
+	}
+
+	config := map[string]string{}
+	config["certificateId"] = certificateID
+
+	return c.commonAPI("PUT", "internal", "/cluster/me/web_signed_cert", config, httpTimeout)
+}
+
+// SetLoginBanner configures the legal banner "text" that is displayed before users log in to the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster login banner is already configured with the provided text.
+//
+//	The full API response for PUT /internal/cluster/me/login_banner
+func (c *Credentials) SetLoginBanner(text string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	loginBanner, err := c.commonAPI("GET", "internal", "/cluster/me/login_banner", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentText, _ := getString(loginBanner, "loginBanner"); currentText == text {
+		return "No change required. The Rubrik cluster login banner is already configured with the provided text.", nil
+	}
+
+	config := map[string]string{}
+	config["loginBanner"] = text
+
+	return c.commonAPI("PUT", "internal", "/cluster/me/login_banner", config, httpTimeout)
+}
+
+// AcceptEULA accepts the Rubrik end user license agreement on behalf of the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The end user license agreement has already been accepted.
+//
+//	The full API response for POST /internal/cluster/me/accept_eula
+func (c *Credentials) AcceptEULA(timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	eula, err := c.commonAPI("GET", "internal", "/cluster/me/eula", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAccepted, _ := apiValue(eula, []string{"isAccepted"}); isAccepted == true {
+		return "No change required. The end user license agreement has already been accepted.", nil
+	}
+
+	return c.commonAPI("POST", "internal", "/cluster/me/accept_eula", nil, httpTimeout)
+}
+
+// ConfigureDNSServers provides the connection information for the DNS Servers used by the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with the provided DNS servers.
+//
+//	The full API response for POST /internal/cluster/me/dns_nameserver
+func (c *Credentials) ConfigureDNSServers(serverIP []string, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	currentDNSServers := c.Get("internal", "/cluster/me/dns_nameserver", httpTimeout).(map[string]interface{})["data"].([]interface{})
+
+	if stringEq(serverIP, currentDNSServers) {
+		return "No change required. The Rubrik cluster is already configured with the provided DNS servers."
+	}
+
+	return c.Post("internal", "/cluster/me/dns_nameserver", serverIP, httpTimeout)
+
+}
+
+// ConfigureSearchDomain provides the connection information for the DNS search domains used by the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with the provided DNS search domains.
+//
+//	The full API response for POST /internal/cluster/me/dns_search_domain
+func (c *Credentials) ConfigureSearchDomain(searchDomain []string, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	currentSearchDomains := c.Get("internal", "/cluster/me/dns_search_domain", httpTimeout).(map[string]interface{})["data"].([]interface{})
+
+	if stringEq(searchDomain, currentSearchDomains) {
+		return "No change required. The Rubrik cluster is already configured with the provided DNS search domains."
+	}
+
+	return c.Post("internal", "/cluster/me/dns_search_domain", searchDomain, httpTimeout)
+
+}
+
+// ConfigureSMTPSettings provides the connection information to send notification email messages for delivery to
+// the administrator accounts.
+//
+// Valid encryption choices are:
+//
+//	NONE, SSL, and STARTTLS
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with the provided SMTP settings.
+//
+//	The full API response for POST /internal/smtp_instance
+//
+// The full API response for PATCH /smtp_instance/{smtpID}
+func (c *Credentials) ConfigureSMTPSettings(hostname, fromEmail, smtpUsername, smtpPassword, encryption string, port int, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validEncryption := map[string]bool{
+		"NONE":     true,
+		"SSL":      true,
+		"STARTTLS": true,
+	}
+
+	if validEncryption[encryption] == false {
+		log.Fatalf("Error: The 'encryption' must be 'NONE', 'SSL' or 'STARTTLS'.")
+	}
+
+	config := map[string]interface{}{}
+	config["smtpSecurity"] = encryption
+	config["smtpHostname"] = hostname
+	config["smtpPort"] = port
+	config["smtpUsername"] = smtpUsername
+	config["fromEmailId"] = fromEmail
+
+	getSMTPSettings := c.Get("internal", "/smtp_instance", httpTimeout)
+
+	if total, _ := Float64Value(getSMTPSettings.(map[string]interface{})["total"]); total == 0 {
+		config["smtpPassword"] = smtpPassword
+		return c.Post("internal", "/smtp_instance", config, httpTimeout)
+	}
+
+	currentSMTPSettings := getSMTPSettings.(map[string]interface{})["data"].([]interface{})[0]
+
+	smtpID := currentSMTPSettings.(map[string]interface{})["id"]
+	delete(currentSMTPSettings.(map[string]interface{}), "id")
+	// Convert the smtpPort to int for comparison
+	smtpPort, _ := Int64Value(currentSMTPSettings.(map[string]interface{})["smtpPort"])
+	currentSMTPSettings.(map[string]interface{})["smtpPort"] = int(smtpPort)
+
+	checkConfig := reflect.DeepEqual(config, currentSMTPSettings)
+	if checkConfig {
+		return fmt.Sprintf("No change required. The Rubrik cluster is already configured with the provided SMTP settings.")
+	}
+
+	return c.Patch("internal", fmt.Sprintf("/smtp_instance/%s", smtpID), config, httpTimeout)
+}
+
+// ConfigureVLAN provides the VLAN VLAN tagging information which is an optional feature that allows a Rubrik cluster to
+// efficiently switch network traffic using Virtual Local Area Networks. The ips map should be in a {nodeName:IP} format.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with the provided VLAN information.
+//
+//	The full API response for POST /internal/cluster/me/vlan
+func (c *Credentials) ConfigureVLAN(netmask string, vlan int, ips map[string]string, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	config := map[string]interface{}{}
+	config["vlan"] = vlan
+	config["netmask"] = netmask
+	nodeIPInterfaces := []interface{}{}
+	for k, v := range ips {
+		nodeIPInterfaces = append(nodeIPInterfaces, map[string]interface{}{"node": k, "ip": v})
+	}
+	config["interfaces"] = nodeIPInterfaces
+
+	getCurrentVLANs := c.Get("internal", "/cluster/me/vlan", httpTimeout)
+
+	if total, _ := Float64Value(getCurrentVLANs.(map[string]interface{})["total"]); total != 0 {
+		currentVLANs := getCurrentVLANs.(map[string]interface{})["data"].([]interface{})[0]
+		// Convert int from float64 to int
+		vlan, _ := Int64Value(currentVLANs.((map[string]interface{}))["vlan"])
+		currentVLANs.((map[string]interface{}))["vlan"] = int(vlan)
+
+		checkConfig := reflect.DeepEqual(config, currentVLANs)
+		if checkConfig {
+			return "No change required. The Rubrik cluster is already configured with the provided VLAN information."
+		}
+
+	}
+	return c.Post("internal", "/cluster/me/vlan", config, httpTimeout)
+
+}
+
+// GetClusterVIP returns the floating IP addresses currently assigned to the Rubrik cluster.
+func (c *Credentials) GetClusterVIP(timeout ...int) ([]string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/cluster/me/floating_ip", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	floatingIPSummary, _ := apiRequest.(map[string]interface{})
+	floatingIPs, ok := floatingIPSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the floating IPs from the Rubrik cluster.")
+	}
+
+	vips := []string{}
+	for _, v := range floatingIPs {
+		floatingIP, _ := v.(map[string]interface{})
+		if ip, ok := floatingIP["ip"].(string); ok {
+			vips = append(vips, ip)
+		}
+	}
+
+	return vips, nil
+}
+
+// SetClusterVIP assigns the provided floating IP addresses to the Rubrik cluster, replacing any floating IPs that are currently
+// configured.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is already configured with the provided floating IPs.
+//
+//	The full API response for PUT /internal/cluster/me/floating_ip
+func (c *Credentials) SetClusterVIP(vips []string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(vips) == 0 {
+		return nil, errors.New("You must provide at least one floating IP.")
+	}
+
+	for _, vip := range vips {
+		if net.ParseIP(vip) == nil {
+			return nil, fmt.Errorf("'%s' is not a valid IP address.", vip)
+		}
+	}
+
+	currentVIPs, err := c.GetClusterVIP(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIPs := make([]interface{}, len(currentVIPs))
+	for i, vip := range currentVIPs {
+		currentIPs[i] = vip
+	}
+
+	if stringEq(append([]string{}, vips...), currentIPs) {
+		return "No change required. The Rubrik cluster is already configured with the provided floating IPs.", nil
+	}
+
+	return c.commonAPI("PUT", "internal", "/cluster/me/floating_ip", vips, httpTimeout)
+}
+
+// AddVCenter adds a vCenter Server to the Rubrik cluster. The optional "caCerts" are the PEM encoded CA certificates used to verify
+// the certificate of the vCenter Server.
+//
+// The function will return one of the following:
+//	No change required. The vCenter '{vCenterHostname}' has already been added to the Rubrik cluster.
+//
+//	The full API response for POST /v1/vmware/vcenter
+func (c *Credentials) AddVCenter(vCenterHostname, username, password string, caCerts ...string) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(nil)
+
+	return c.addvCenter(vCenterHostname, username, password, strings.Join(caCerts, "\n"), "", httpTimeout)
+}
+
+// AddvCenter connects to the Rubrik cluster to a new vCenter instance.
+//
+// The function will return one of the following:
+//	No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
+//
+//	The job status URL for POST /v1/vmware/vcenter
+func (c *Credentials) AddvCenter(vCenterIP, vCenterUsername, vCenterPassword string, vmLinking bool, timeout ...int) string {
+
+	return c.AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPassword, "", vmLinking, timeout...)
+}
+
+// AddvCenterWithCert connects to the Rubrik cluster to a new vCenter instance using a CA certificate.
+//
+// The function will return one of the following:
+//	No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
+//
+//	The job status URL for POST /v1/vmware/vcenter
+func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPassword, caCertificate string, vmLinking bool, timeout ...int) string {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	conflictResolution := "NoConflictResolution"
+	if vmLinking {
+		conflictResolution = "AllowAutoConflictResolution"
+	}
+
+	addvCenter, err := c.addvCenter(vCenterIP, vCenterUsername, vCenterPassword, caCertificate, conflictResolution, httpTimeout)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	if message, ok := addvCenter.(string); ok {
+		return message
+	}
+
+	jobURL, err := jobStatusURL(addvCenter)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	return jobURL
+}
+
+// addvCenter adds a vCenter to the Rubrik cluster unless a vCenter with the same hostname has already been added. The
+// "caCertificate" and "conflictResolution" settings are only sent when they are not blank strings.
+func (c *Credentials) addvCenter(hostname, username, password, caCertificate, conflictResolution string, timeout int) (interface{}, error) {
+
+	currentVCenter, err := c.commonAPI("GET", "v1", "/vmware/vcenter?primary_cluster_id=local", nil, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	vCenters, _ := currentVCenter.(map[string]interface{})
+	vCenterList, _ := vCenters["data"].([]interface{})
+	for _, v := range vCenterList {
+		if vCenter, ok := v.(map[string]interface{}); ok && vCenter["hostname"] == hostname {
+			return fmt.Sprintf("No change required. The vCenter '%s' has already been added to the Rubrik cluster.", hostname), nil
+		}
+	}
+
+	config := map[string]string{}
+	config["hostname"] = hostname
+	config["username"] = username
+	config["password"] = password
+	if len(conflictResolution) != 0 {
+		config["conflictResolutionAuthz"] = conflictResolution
+	}
+	if len(caCertificate) != 0 {
+		config["caCerts"] = caCertificate
+	}
+
+	return c.commonAPI("POST", "v1", "/vmware/vcenter", config, timeout)
+}
+
+// RefreshvCenter refreshes the metadata of a vCenter that has already been added to the Rubrik cluster so that newly created
+// virtual machines are discovered.
+//
+// The function will return:
+//	The job status URL for the vCenter refresh
+func (c *Credentials) RefreshvCenter(vCenterName string, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	vCenterID, err := c.ObjectID(vCenterName, "vcenter")
+	if err != nil {
+		return "", err
+	}
+
+	config := map[string]string{}
+
+	refresh, err := c.commonAPI("POST", "v1", fmt.Sprintf("/vmware/vcenter/%s/refresh", vCenterID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return jobStatusURL(refresh)
+}
+
+// GetVMwareGuestCredentials returns the guest OS credentials the Rubrik cluster uses to take application-consistent snapshots of
+// VMware virtual machines. Passwords are never returned by the Rubrik cluster.
+func (c *Credentials) GetVMwareGuestCredentials(timeout ...int) ([]interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	guestCredentials, err := c.commonAPI("GET", "internal", "/vmware/guest_credential", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	guestCredentialList, _ := guestCredentials.(map[string]interface{})
+	data, ok := guestCredentialList["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the guest OS credentials from the Rubrik cluster.")
 	}
 
-	clusterSummary := c.Get("v1", "/cluster/me")
+	return data, nil
+}
+
+// guestCredentialID returns the ID of the guest OS credential matching the provided "username" and "domain" or a blank string
+// if no match is found.
+func (c *Credentials) guestCredentialID(username, domain string, timeout int) (string, error) {
 
-	if clusterSummary.(map[string]interface{})["timezone"].(map[string]interface{})["timezone"] == timezone {
-		return fmt.Sprintf("No change required. The Rubrik cluster is already configured with '%s' as it's timezone.", timezone)
+	guestCredentials, err := c.GetVMwareGuestCredentials(timeout)
+	if err != nil {
+		return "", err
 	}
 
-	config := map[string]interface{}{}
-	config["timezone"] = map[string]string{}
-	config["timezone"].(map[string]string)["timezone"] = timezone
+	for _, v := range guestCredentials {
+		credential, ok := v.(map[string]interface{})
+		if ok != true {
+			continue
+		}
 
-	return c.Patch("v1", "/cluster/me", config, httpTimeout)
+		currentDomain, _ := credential["domain"].(string)
+		if credential["username"] == username && currentDomain == domain {
+			credentialID, _ := credential["id"].(string)
+			return credentialID, nil
+		}
+	}
 
+	return "", nil
 }
 
-// ConfigureNTP provides the connection information for the NTP servers used for time synchronization.
+// SetVMwareGuestCredential adds the guest OS credential used for application-consistent (VSS) snapshots of VMware virtual machines. If a
+// credential with the same "username" and "domain" already exists, its password is updated instead. Use a blank "domain" for local
+// accounts.
 //
 // The function will return one of the following:
-//	No change required. The NTP server(s) {ntpServers} has already been added to the Rubrik cluster.
+//	The full API response for POST /internal/vmware/guest_credential
 //
-//	The full API response for POST /internal/cluster/me/ntp_server
-func (c *Credentials) ConfigureNTP(ntpServers []string, timeout ...int) interface{} {
-
-	httpTimeout := httpTimeout(timeout)
-
-	clusterNTP := c.Get("internal", "/cluster/me/ntp_server").(map[string]interface{})["data"]
+//	The full API response for PUT /internal/vmware/guest_credential/{id}
+func (c *Credentials) SetVMwareGuestCredential(username, password, domain string, timeout ...int) (interface{}, error) {
 
-	// Convert the clusterNTP Slice to []string
-	var currentNTPServers = []string{}
-	for _, server := range clusterNTP.([]interface{}) {
-		currentNTPServers = append(currentNTPServers, server.(string))
+	httpTimeout := c.httpTimeout(timeout)
 
+	if len(username) == 0 {
+		return nil, errors.New("The guest credential 'username' must not be a blank string.")
 	}
 
-	updateNTP := false
-	if len(ntpServers) == len(currentNTPServers) {
-		for i := range ntpServers {
-			if ntpServers[i] != currentNTPServers[i] {
-				updateNTP = true
-			}
-		}
-	} else {
-		updateNTP = true
+	config := map[string]string{}
+	config["username"] = username
+	config["password"] = password
+	if len(domain) != 0 {
+		config["domain"] = domain
 	}
 
-	if updateNTP {
-		return c.Post("internal", "/cluster/me/ntp_server", ntpServers, httpTimeout)
+	credentialID, err := c.guestCredentialID(username, domain, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	return fmt.Sprintf("No change required. The NTP server(s) %s has already been added to the Rubrik cluster.", ntpServers)
+	if len(credentialID) != 0 {
+		return c.commonAPI("PUT", "internal", fmt.Sprintf("/vmware/guest_credential/%s", credentialID), config, httpTimeout)
+	}
 
+	return c.commonAPI("POST", "internal", "/vmware/guest_credential", config, httpTimeout)
 }
 
-// ConfigureSyslog enables the Rubrik cluster to send syslog server messages that are based on the events that also appear in
-// the Activity Log to the provided Syslog Server.
-//
-// Valid protocol choices are:
-//
-//	UDP, TCP
+// DeleteVMwareGuestCredential removes the guest OS credential matching the provided "username" and "domain" from the Rubrik cluster.
 //
 // The function will return one of the following:
-//	No change required. The Rubrik cluster is already configured to use the syslog server '{syslogIP}' on port '{port}' using the '{protocol}' protocol.
+//	No change required. The guest credential '{username}' is not present on the Rubrik cluster.
 //
-//	The full API response for POST /internal/syslog
-func (c *Credentials) ConfigureSyslog(syslogIP, protocol string, port float64, timeout ...int) interface{} {
+//	The full API response for DELETE /internal/vmware/guest_credential/{id}
+func (c *Credentials) DeleteVMwareGuestCredential(username, domain string, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	validProtocols := map[string]bool{
-		"UDP": true,
-		"TCP": true,
+	credentialID, err := c.guestCredentialID(username, domain, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	if validProtocols[protocol] == false {
-		log.Fatalf("Error: The 'protocol' must be 'UDP' or 'TCP'.")
+	if len(credentialID) == 0 {
+		return fmt.Sprintf("No change required. The guest credential '%s' is not present on the Rubrik cluster.", username), nil
 	}
 
-	config := map[string]interface{}{}
-	config["hostname"] = syslogIP
-	config["protocol"] = protocol
-	config["port"] = port
+	return c.commonAPI("DELETE", "internal", fmt.Sprintf("/vmware/guest_credential/%s", credentialID), nil, httpTimeout)
+}
 
-	clusterSyslog := c.Get("internal", "/syslog").(map[string]interface{})["data"]
+// AddLDAPService configures an LDAP or Active Directory service the Rubrik cluster can use to authenticate users. The hosts in "serverHosts"
+// are contacted in the order provided, with each subsequent host used for failover. "advancedOptions" may contain any additional
+// settings supported by the API, such as "userSearchFilter" or "groupMemberAttr", or be nil.
+//
+// The function will return one of the following:
+//	No change required. The LDAP service '{name}' is already configured on the Rubrik cluster.
+//
+//	The full API response for POST /internal/ldap_service
+func (c *Credentials) AddLDAPService(name, baseDN, bindUserName, bindUserPassword string, serverHosts []string, advancedOptions map[string]interface{}, timeout ...int) (interface{}, error) {
 
-	if len(clusterSyslog.([]interface{})) > 0 {
+	httpTimeout := c.httpTimeout(timeout)
 
-		activeSyslog := clusterSyslog.([]interface{})[0]
-		// Remove id for comparison
-		delete(activeSyslog.(map[string]interface{}), "id")
+	if len(serverHosts) == 0 {
+		return nil, errors.New("The 'serverHosts' must contain at least one LDAP server.")
+	}
 
-		deleteSyslog := false
-		if len(config) == len(activeSyslog.(map[string]interface{})) {
-			for i := range config {
-				if config[i] != activeSyslog.(map[string]interface{})[i] {
-					deleteSyslog = true
+	apiRequest, err := c.commonAPI("GET", "internal", "/ldap_service", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-				}
-			}
+	ldapSummary, _ := apiRequest.(map[string]interface{})
+	ldapServices, ok := ldapSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the LDAP services from the Rubrik cluster.")
+	}
 
+	for _, v := range ldapServices {
+		ldapService, _ := v.(map[string]interface{})
+		if ldapService["name"] == name {
+			return fmt.Sprintf("No change required. The LDAP service '%s' is already configured on the Rubrik cluster.", name), nil
 		}
-		if deleteSyslog {
-			c.Delete("internal", "/syslog/1")
-		} else {
-			return fmt.Sprintf("No change required. The Rubrik cluster is already configured to use the syslog server '%s' on port '%d' using the '%s' protocol.", syslogIP, int(port), protocol)
-		}
+	}
 
+	config := map[string]interface{}{}
+	config["name"] = name
+	config["baseDn"] = baseDN
+	config["bindUserName"] = bindUserName
+	config["bindUserPassword"] = bindUserPassword
+	config["authServers"] = serverHosts
+	if len(advancedOptions) != 0 {
+		config["advancedOptions"] = advancedOptions
 	}
-	return c.Post("internal", "/syslog", config, httpTimeout)
 
+	return c.commonAPI("POST", "internal", "/ldap_service", config, httpTimeout)
 }
 
-// ConfigureDNSServers provides the connection information for the DNS Servers used by the Rubrik cluster.
-//
-// The function will return one of the following:
-//	No change required. The Rubrik cluster is already configured with the provided DNS servers.
-//
-//	The full API response for POST /internal/cluster/me/dns_nameserver
-func (c *Credentials) ConfigureDNSServers(serverIP []string, timeout ...int) interface{} {
+// LDAPConnectionTest contains the result of testing an LDAP service. "Details" contains the reason the test failed, if any.
+type LDAPConnectionTest struct {
+	Connected bool
+	Details   string
+}
 
-	httpTimeout := httpTimeout(timeout)
+// TestLDAPConnection verifies that the Rubrik cluster can connect and bind to the LDAP or Active Directory service "serviceName" and
+// search its directory. A service that fails the test is reported through the returned LDAPConnectionTest rather than an error.
+func (c *Credentials) TestLDAPConnection(serviceName string, timeout ...int) (*LDAPConnectionTest, error) {
 
-	currentDNSServers := c.Get("internal", "/cluster/me/dns_nameserver", httpTimeout).(map[string]interface{})["data"].([]interface{})
+	httpTimeout := c.httpTimeout(timeout)
 
-	if stringEq(serverIP, currentDNSServers) {
-		return "No change required. The Rubrik cluster is already configured with the provided DNS servers."
+	ldapServiceID, err := c.namedObjectID("internal", "/ldap_service", serviceName, "LDAP service", httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	return c.Post("internal", "/cluster/me/dns_nameserver", serverIP, httpTimeout)
+	_, err = c.commonAPI("POST", "internal", fmt.Sprintf("/ldap_service/%s/test_connection", ldapServiceID), map[string]string{}, httpTimeout)
+	if apiError, ok := err.(*APIError); ok && apiError.StatusCode == http.StatusBadRequest {
+		return &LDAPConnectionTest{Connected: false, Details: apiError.Error()}, nil
+	} else if err != nil {
+		return nil, err
+	}
 
+	return &LDAPConnectionTest{Connected: true}, nil
 }
 
-// ConfigureSearchDomain provides the connection information for the DNS search domains used by the Rubrik cluster.
-//
-// The function will return one of the following:
-//	No change required. The Rubrik cluster is already configured with the provided DNS search domains.
-//
-//	The full API response for POST /internal/cluster/me/dns_search_domain
-func (c *Credentials) ConfigureSearchDomain(searchDomain []string, timeout ...int) interface{} {
+// NetworkThrottle contains the network throttle configuration of the Rubrik cluster for either replication or archival traffic.
+// "DefaultThrottleLimit" is in Mbps.
+type NetworkThrottle struct {
+	ResourceID           string  `json:"resourceId"`
+	IsEnabled            bool    `json:"isEnabled"`
+	DefaultThrottleLimit float64 `json:"defaultThrottleLimit"`
+}
 
-	httpTimeout := httpTimeout(timeout)
+// GetReplicationThrottle returns the current replication network throttle configuration of the Rubrik cluster.
+func (c *Credentials) GetReplicationThrottle(timeout ...int) (*NetworkThrottle, error) {
 
-	currentSearchDomains := c.Get("internal", "/cluster/me/dns_search_domain", httpTimeout).(map[string]interface{})["data"].([]interface{})
+	httpTimeout := c.httpTimeout(timeout)
 
-	if stringEq(searchDomain, currentSearchDomains) {
-		return "No change required. The Rubrik cluster is already configured with the provided DNS search domains."
-	}
+	return c.networkThrottle("ReplicationEgress", httpTimeout)
+}
 
-	return c.Post("internal", "/cluster/me/dns_search_domain", searchDomain, httpTimeout)
+// GetArchivalThrottle returns the current archival network throttle configuration of the Rubrik cluster.
+func (c *Credentials) GetArchivalThrottle(timeout ...int) (*NetworkThrottle, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
 
+	return c.networkThrottle("ArchivalEgress", httpTimeout)
 }
 
-// ConfigureSMTPSettings provides the connection information to send notification email messages for delivery to
-// the administrator accounts.
+// SetReplicationThrottle enables or disables the throttling of outgoing replication traffic and sets the default throttle limit
+// to "mbps".
 //
-// Valid encryption choices are:
+// The function will return one of the following:
+//	No change required. The ReplicationEgress network throttle is already configured with the provided settings.
 //
-//	NONE, SSL, and STARTTLS
+//	The full API response for PATCH /internal/network_throttle/ReplicationEgress
+func (c *Credentials) SetReplicationThrottle(enabled bool, mbps int, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.setNetworkThrottle("ReplicationEgress", enabled, mbps, httpTimeout)
+}
+
+// SetArchivalThrottle enables or disables the throttling of outgoing archival traffic and sets the default throttle limit to "mbps".
 //
 // The function will return one of the following:
-//	No change required. The Rubrik cluster is already configured with the provided SMTP settings.
-//
-//	The full API response for POST /internal/smtp_instance
+//	No change required. The ArchivalEgress network throttle is already configured with the provided settings.
 //
-// The full API response for PATCH /smtp_instance/{smtpID}
-func (c *Credentials) ConfigureSMTPSettings(hostname, fromEmail, smtpUsername, smtpPassword, encryption string, port int, timeout ...int) interface{} {
+//	The full API response for PATCH /internal/network_throttle/ArchivalEgress
+func (c *Credentials) SetArchivalThrottle(enabled bool, mbps int, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	validEncryption := map[string]bool{
-		"NONE":     true,
-		"SSL":      true,
-		"STARTTLS": true,
+	return c.setNetworkThrottle("ArchivalEgress", enabled, mbps, httpTimeout)
+}
+
+// networkThrottle returns the network throttle configuration of the provided "resourceID".
+func (c *Credentials) networkThrottle(resourceID string, timeout int) (*NetworkThrottle, error) {
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/network_throttle", nil, timeout)
+	if err != nil {
+		return nil, err
 	}
 
-	if validEncryption[encryption] == false {
-		log.Fatalf("Error: The 'encryption' must be 'NONE', 'SSL' or 'STARTTLS'.")
+	throttleSummary, _ := apiRequest.(map[string]interface{})
+	throttles, ok := throttleSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the network throttles from the Rubrik cluster.")
 	}
 
-	config := map[string]interface{}{}
-	config["smtpSecurity"] = encryption
-	config["smtpHostname"] = hostname
-	config["smtpPort"] = port
-	config["smtpUsername"] = smtpUsername
-	config["fromEmailId"] = fromEmail
+	for _, v := range throttles {
+		throttle, _ := v.(map[string]interface{})
+		if throttle["resourceId"] == resourceID {
+			var networkThrottle NetworkThrottle
+			if err := convertResponse(throttle, &networkThrottle); err != nil {
+				return nil, fmt.Errorf("Unable to read the %s network throttle: %s", resourceID, err)
+			}
+			return &networkThrottle, nil
+		}
+	}
 
-	getSMTPSettings := c.Get("internal", "/smtp_instance", httpTimeout)
+	return nil, fmt.Errorf("The Rubrik cluster does not contain a %s network throttle.", resourceID)
+}
 
-	if getSMTPSettings.(map[string]interface{})["total"] == float64(0) {
-		config["smtpPassword"] = smtpPassword
-		return c.Post("internal", "/smtp_instance", config, httpTimeout)
+// setNetworkThrottle updates the network throttle configuration of the provided "resourceID".
+func (c *Credentials) setNetworkThrottle(resourceID string, enabled bool, mbps int, timeout int) (interface{}, error) {
+
+	if enabled && mbps <= 0 {
+		return nil, errors.New("The 'mbps' must be greater than 0 when the network throttle is enabled.")
 	}
 
-	currentSMTPSettings := getSMTPSettings.(map[string]interface{})["data"].([]interface{})[0]
+	currentThrottle, err := c.networkThrottle(resourceID, timeout)
+	if err != nil {
+		return nil, err
+	}
 
-	smtpID := currentSMTPSettings.(map[string]interface{})["id"]
-	delete(currentSMTPSettings.(map[string]interface{}), "id")
-	// Convert the smtpPort to int for comparison
-	currentSMTPSettings.(map[string]interface{})["smtpPort"] = int(currentSMTPSettings.(map[string]interface{})["smtpPort"].(float64))
+	if currentThrottle.IsEnabled == enabled && (enabled == false || currentThrottle.DefaultThrottleLimit == float64(mbps)) {
+		return fmt.Sprintf("No change required. The %s network throttle is already configured with the provided settings.", resourceID), nil
+	}
 
-	checkConfig := reflect.DeepEqual(config, currentSMTPSettings)
-	if checkConfig {
-		return fmt.Sprintf("No change required. The Rubrik cluster is already configured with the provided SMTP settings.")
+	config := map[string]interface{}{}
+	config["isEnabled"] = enabled
+	if enabled {
+		config["defaultThrottleLimit"] = mbps
 	}
 
-	return c.Patch("internal", fmt.Sprintf("/smtp_instance/%s", smtpID), config, httpTimeout)
+	return c.commonAPI("PATCH", "internal", fmt.Sprintf("/network_throttle/%s", resourceID), config, timeout)
 }
 
-// ConfigureVLAN provides the VLAN VLAN tagging information which is an optional feature that allows a Rubrik cluster to
-// efficiently switch network traffic using Virtual Local Area Networks. The ips map should be in a {nodeName:IP} format.
+// ClusterProxy contains the outbound proxy configuration of the Rubrik cluster. The proxy password is never returned by the Rubrik
+// cluster.
+type ClusterProxy struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	Username string `json:"username"`
+}
+
+// GetClusterProxy returns the outbound proxy configuration of the Rubrik cluster.
+func (c *Credentials) GetClusterProxy(timeout ...int) (*ClusterProxy, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/node_management/proxy_config", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterProxy ClusterProxy
+	if err := convertResponse(apiRequest, &clusterProxy); err != nil {
+		return nil, fmt.Errorf("Unable to read the proxy configuration from the Rubrik cluster: %s", err)
+	}
+
+	return &clusterProxy, nil
+}
+
+// ConfigureClusterProxy configures the outbound proxy the Rubrik cluster uses for services such as archival and support tunnel
+// access. Because the current password can not be read from the Rubrik cluster, only the "host", "port", "protocol", and
+// "username" are compared when determining if a change is required.
+//
+// Valid "protocol" choices are:
+//
+//	HTTP, HTTPS, SOCKS5
 //
 // The function will return one of the following:
-//	No change required. The Rubrik cluster is already configured with the provided VLAN information.
+//	No change required. The Rubrik cluster is already configured to use the '{host}' proxy.
 //
-//	The full API response for POST /internal/cluster/me/vlan
-func (c *Credentials) ConfigureVLAN(netmask string, vlan int, ips map[string]string, timeout ...int) interface{} {
+//	The full API response for PATCH /internal/node_management/proxy_config
+func (c *Credentials) ConfigureClusterProxy(host string, port int, username, password, protocol string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validProtocol := map[string]bool{
+		"HTTP":   true,
+		"HTTPS":  true,
+		"SOCKS5": true,
+	}
+
+	if validProtocol[protocol] == false {
+		return nil, errors.New("The 'protocol' must be 'HTTP', 'HTTPS', or 'SOCKS5'.")
+	}
+
+	currentProxy, err := c.GetClusterProxy(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-	httpTimeout := httpTimeout(timeout)
+	if currentProxy.Host == host && currentProxy.Port == port && currentProxy.Protocol == protocol && currentProxy.Username == username {
+		return fmt.Sprintf("No change required. The Rubrik cluster is already configured to use the '%s' proxy.", host), nil
+	}
 
 	config := map[string]interface{}{}
-	config["vlan"] = vlan
-	config["netmask"] = netmask
-	nodeIPInterfaces := []interface{}{}
-	for k, v := range ips {
-		nodeIPInterfaces = append(nodeIPInterfaces, map[string]interface{}{"node": k, "ip": v})
+	config["host"] = host
+	config["port"] = port
+	config["protocol"] = protocol
+	if len(username) != 0 {
+		config["username"] = username
+		config["password"] = password
 	}
-	config["interfaces"] = nodeIPInterfaces
 
-	getCurrentVLANs := c.Get("internal", "/cluster/me/vlan", httpTimeout)
+	return c.commonAPI("PATCH", "internal", "/node_management/proxy_config", config, httpTimeout)
+}
 
-	if getCurrentVLANs.(map[string]interface{})["total"] != float64(0) {
-		currentVLANs := getCurrentVLANs.(map[string]interface{})["data"].([]interface{})[0]
-		// Convert int from float64 to int
-		currentVLANs.((map[string]interface{}))["vlan"] = int(currentVLANs.((map[string]interface{}))["vlan"].(float64))
+// DeleteClusterProxy removes the outbound proxy configuration from the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The Rubrik cluster is not configured to use a proxy.
+//
+//	The full API response for DELETE /internal/node_management/proxy_config
+func (c *Credentials) DeleteClusterProxy(timeout ...int) (interface{}, error) {
 
-		checkConfig := reflect.DeepEqual(config, currentVLANs)
-		if checkConfig {
-			return "No change required. The Rubrik cluster is already configured with the provided VLAN information."
-		}
+	httpTimeout := c.httpTimeout(timeout)
 
+	currentProxy, err := c.GetClusterProxy(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(currentProxy.Host) == 0 {
+		return "No change required. The Rubrik cluster is not configured to use a proxy.", nil
 	}
-	return c.Post("internal", "/cluster/me/vlan", config, httpTimeout)
 
+	return c.commonAPI("DELETE", "internal", "/node_management/proxy_config", nil, httpTimeout)
 }
 
-// AddvCenter connects to the Rubrik cluster to a new vCenter instance.
+// RegisterWithPolaris registers the Rubrik cluster with the Rubrik Polaris account "polarisAccount" using the provided Polaris service
+// account "clientID" and "clientSecret". The "polarisAccount" may either be the account name (ex: rubrikgo) or the full account
+// domain (ex: rubrikgo.my.rubrik.com).
 //
 // The function will return one of the following:
-//	No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
+//	No change required. The Rubrik cluster is already registered with '{polarisAccount}'.
 //
-//	The full API response for POST /v1/vmware/vcenter
-func (c *Credentials) AddvCenter(vCenterIP, vCenterUsername, vCenterPassword string, vmLinking bool, timeout ...int) string {
+//	The full API response for POST /internal/cluster/me/global_manager
+func (c *Credentials) RegisterWithPolaris(polarisAccount, clientID, clientSecret string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
 
-	httpTimeout := httpTimeout(timeout)
+	if len(polarisAccount) == 0 || len(clientID) == 0 || len(clientSecret) == 0 {
+		return nil, errors.New("The 'polarisAccount', 'clientID', and 'clientSecret' must not be blank strings.")
+	}
 
-	currentVCenter := c.Get("v1", "/vmware/vcenter?primary_cluster_id=local", httpTimeout).(map[string]interface{})
+	if strings.Contains(polarisAccount, ".") == false {
+		polarisAccount = fmt.Sprintf("%s.my.rubrik.com", polarisAccount)
+	}
 
-	for _, v := range currentVCenter["data"].([]interface{}) {
+	apiRequest, err := c.commonAPI("GET", "internal", "/cluster/me/global_manager", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-		if v.(interface{}).(map[string]interface{})["hostname"].(string) == vCenterIP {
-			return fmt.Sprintf("No change required. The vCenter '%s' has already been added to the Rubrik cluster.", vCenterIP)
+	globalManager, _ := apiRequest.(map[string]interface{})
+	if isConnected, _ := globalManager["isConnected"].(bool); isConnected {
+		if globalManagerURL, _ := globalManager["url"].(string); strings.Contains(globalManagerURL, polarisAccount) {
+			return fmt.Sprintf("No change required. The Rubrik cluster is already registered with '%s'.", polarisAccount), nil
 		}
+
+		return nil, fmt.Errorf("The Rubrik cluster is already registered with a different Polaris account (%s).", globalManager["url"])
 	}
 
 	config := map[string]string{}
-	config["hostname"] = vCenterIP
-	config["username"] = vCenterUsername
-	config["password"] = vCenterPassword
-	if vmLinking {
-		config["conflictResolutionAuthz"] = "AllowAutoConflictResolution"
-	} else if vmLinking == false {
-		config["conflictResolutionAuthz"] = "NoConflictResolution"
+	config["polarisAccount"] = polarisAccount
+	config["clientId"] = clientID
+	config["clientSecret"] = clientSecret
+
+	return c.commonAPI("POST", "internal", "/cluster/me/global_manager", config, httpTimeout)
+}
+
+// ReplicationTarget contains the details of a Rubrik cluster configured as a replication target.
+type ReplicationTarget struct {
+	ID                   string `json:"id"`
+	TargetClusterUUID    string `json:"targetClusterUuid"`
+	TargetClusterName    string `json:"targetClusterName"`
+	TargetClusterAddress string `json:"targetClusterAddress"`
+	ReplicationSetup     string `json:"replicationSetup"`
+}
+
+// GetReplicationTargets returns all replication targets configured on the Rubrik cluster.
+func (c *Credentials) GetReplicationTargets(timeout ...int) ([]ReplicationTarget, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/replication/target", nil, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	return c.Post("v1", "/vmware/vcenter", config, httpTimeout).(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string)
+	replicationSummary, _ := apiRequest.(map[string]interface{})
+	replicationTargets, ok := replicationSummary["data"]
+	if ok != true {
+		return nil, errors.New("Unable to read the replication targets from the Rubrik cluster.")
+	}
+
+	var targets []ReplicationTarget
+	if err := convertResponse(replicationTargets, &targets); err != nil {
+		return nil, fmt.Errorf("Unable to read the replication targets from the Rubrik cluster: %s", err)
+	}
 
+	return targets, nil
 }
 
-// AddvCenterWithCert connects to the Rubrik cluster to a new vCenter instance using a CA certificate.
+// AddReplicationTarget configures the Rubrik cluster located at "targetClusterAddress" as a replication target over a private network.
+// The "username" and "password" must be for an administrator account on the target Rubrik cluster.
 //
 // The function will return one of the following:
-//	No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
+//	No change required. The Rubrik cluster '{targetClusterAddress}' is already configured as a replication target.
 //
-//	The full API response for POST /v1/vmware/vcenter
-func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPassword, caCertificate string, vmLinking bool, timeout ...int) string {
-
-	httpTimeout := httpTimeout(timeout)
+//	The full API response for POST /internal/replication/target
+func (c *Credentials) AddReplicationTarget(targetClusterAddress, username, password string, timeout ...int) (interface{}, error) {
 
-	currentVCenter := c.Get("v1", "/vmware/vcenter?primary_cluster_id=local", httpTimeout).(map[string]interface{})
+	httpTimeout := c.httpTimeout(timeout)
 
-	for _, v := range currentVCenter["data"].([]interface{}) {
+	currentTargets, err := c.GetReplicationTargets(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-		if v.(interface{}).(map[string]interface{})["hostname"].(string) == vCenterIP {
-			return fmt.Sprintf("No change required. The vCenter '%s' has already been added to the Rubrik cluster.", vCenterIP)
+	for _, target := range currentTargets {
+		if target.TargetClusterAddress == targetClusterAddress {
+			return fmt.Sprintf("No change required. The Rubrik cluster '%s' is already configured as a replication target.", targetClusterAddress), nil
 		}
 	}
 
 	config := map[string]string{}
-	config["hostname"] = vCenterIP
-	config["username"] = vCenterUsername
-	config["password"] = vCenterPassword
-	if vmLinking {
-		config["conflictResolutionAuthz"] = "AllowAutoConflictResolution"
-	} else if vmLinking == false {
-		config["conflictResolutionAuthz"] = "NoConflictResolution"
+	config["targetClusterAddress"] = targetClusterAddress
+	config["username"] = username
+	config["password"] = password
+	config["setupType"] = "Private Network"
+
+	return c.commonAPI("POST", "internal", "/replication/target", config, httpTimeout)
+}
+
+// ReplicationStatus contains the replication health of a single replication target. "Lag" is the time elapsed since the last successful
+// replication to the target and "FailingSnapshots" contains the name of each object whose most recent replication failed.
+type ReplicationStatus struct {
+	TargetName       string
+	TargetAddress    string
+	LastSync         time.Time
+	Lag              time.Duration
+	FailingSnapshots []string
+}
+
+// replicationEventPages is the maximum number of pages, of 100 events each, read by GetReplicationStatus.
+const replicationEventPages = 10
+
+// GetReplicationStatus returns the replication lag, last successful sync time, and any objects failing to replicate for each
+// replication target configured on the Rubrik cluster. The status is calculated from the most recent 1000 replication events, which
+// are matched to a target through the location of their event series.
+func (c *Credentials) GetReplicationStatus(timeout ...int) ([]ReplicationStatus, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	replicationTargets, err := c.GetReplicationTargets(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	query := "event_type=Replication&limit=100"
+	for page := 0; page < replicationEventPages; page++ {
+		pageEvents, hasMore, err := c.latestEvents(query, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, pageEvents...)
+
+		if hasMore == false || len(pageEvents) == 0 {
+			break
+		}
+
+		query = fmt.Sprintf("event_type=Replication&limit=100&after_id=%s", pageEvents[len(pageEvents)-1].ID)
 	}
-	config["caCerts"] = caCertificate
 
-	return c.Post("v1", "/vmware/vcenter", config, httpTimeout).(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string)
+	replicationStatus := []ReplicationStatus{}
+	for _, target := range replicationTargets {
+		status := ReplicationStatus{
+			TargetName:    target.TargetClusterName,
+			TargetAddress: target.TargetClusterAddress,
+		}
+
+		// Track the most recent replication event of each object to the target
+		latestObjectEvent := map[string]time.Time{}
+		latestObjectStatus := map[string]string{}
+
+		for _, event := range events {
+			// The location of a replication event series is the name of the target cluster
+			if event.Time.IsZero() || len(event.Location) == 0 || event.Location != target.TargetClusterName {
+				continue
+			}
+
+			if event.EventStatus == "Success" && event.Time.After(status.LastSync) {
+				status.LastSync = event.Time
+			}
+
+			if event.Time.After(latestObjectEvent[event.ObjectName]) {
+				latestObjectEvent[event.ObjectName] = event.Time
+				latestObjectStatus[event.ObjectName] = event.EventStatus
+			}
+		}
+
+		for objectName, eventStatus := range latestObjectStatus {
+			if eventStatus == "Failure" {
+				status.FailingSnapshots = append(status.FailingSnapshots, objectName)
+			}
+		}
+		sort.Strings(status.FailingSnapshots)
+
+		if status.LastSync.IsZero() == false {
+			status.Lag = time.Since(status.LastSync)
+		}
+
+		replicationStatus = append(replicationStatus, status)
+	}
 
+	return replicationStatus, nil
 }
 
 // Bootstrap will complete the bootstrap process for a Rubrik cluster and requires a single node to have it's management interface
@@ -481,7 +2291,7 @@ func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPass
 //	The full API response for POST /internal/cluster/me/bootstrap (waitForCompletion is set to false)
 func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains, dnsNameServers, ntpServers []string, nodeConfig map[string]string, enableEncryption, waitForCompletion bool, timeout ...int) interface{} {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
 	// Validate that the Credentials struck only has a node ip configured.
 	if len(c.Username) != 0 {
@@ -518,7 +2328,7 @@ func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, manageme
 	}
 
 	bootstrap := c.Post("internal", "/cluster/me/bootstrap", config, httpTimeout)
-	bootstrapRequestID := bootstrap.(map[string]interface{})["id"].(float64)
+	bootstrapRequestID, _ := Float64Value(bootstrap.(map[string]interface{})["id"])
 
 	if waitForCompletion {
 
@@ -1,28 +1,36 @@
 package rubrikcdm
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"time"
 )
 
-// ObjectID will search the Rubrik cluster for the provided "objectName" and return its ID/
+// ObjectID will search the Rubrik cluster for the provided "objectName" and return its ID. The "hostOS" (Linux or Windows) is required
+// when the "objectType" is filesetTemplate.
 //
-// Valid "awsRegion" choices are:
+// Valid "objectType" choices are:
 //
-//	vmware, sla, vmwareHost, physicalHost, filesetTemplate, managedVolume
-func (c *Credentials) ObjectID(objectName, objectType string, hostOS ...string) string {
+//	vmware, sla, vmwareHost, physicalHost, fileset, filesetTemplate, managedVolume, vcenter, mssql, oracleDB
+func (c *Credentials) ObjectID(objectName, objectType string, hostOS ...string) (string, error) {
 
 	validObjectType := map[string]bool{
 		"vmware":          true,
 		"sla":             true,
 		"vmwareHost":      true,
 		"physicalHost":    true,
+		"fileset":         true,
 		"filesetTemplate": true,
 		"managedVolume":   true,
+		"vcenter":         true,
+		"mssql":           true,
+		"oracleDB":        true,
 	}
 
 	if validObjectType[objectType] == false {
-		log.Fatalf("Error: The 'objectType' must be 'vmware', 'sla', 'vmwareHost', 'physicalHost', 'filesetTemplate', or 'managedVolume'.")
+		return "", errors.New("The 'objectType' must be 'vmware', 'sla', 'vmwareHost', 'physicalHost', 'fileset', 'filesetTemplate', 'managedVolume', 'vcenter', 'mssql', or 'oracleDB'.")
 	}
 
 	var objectSummaryAPIVersion string
@@ -30,307 +38,2187 @@ func (c *Credentials) ObjectID(objectName, objectType string, hostOS ...string)
 	switch objectType {
 	case "vmware":
 		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/vmware/vm?primary_cluster_id=local&is_relic=false&name=%s", objectName)
+		objectSummaryAPIEndpoint = queryEndpoint("/vmware/vm", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {objectName}})
 	case "sla":
 		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/sla_domain?primary_cluster_id=local&name=%s", objectName)
+		objectSummaryAPIEndpoint = queryEndpoint("/sla_domain", url.Values{"primary_cluster_id": {"local"}, "name": {objectName}})
 	case "vmwareHost":
 		objectSummaryAPIVersion = "v1"
 		objectSummaryAPIEndpoint = "/vmware/host?primary_cluster_id=local"
 	case "physicalHost":
 
 		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/host?primary_cluster_id=local&hostname=%s", objectName)
+		objectSummaryAPIEndpoint = queryEndpoint("/host", url.Values{"primary_cluster_id": {"local"}, "hostname": {objectName}})
+	case "fileset":
+		objectSummaryAPIVersion = "v1"
+		objectSummaryAPIEndpoint = queryEndpoint("/fileset", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {objectName}})
 	case "filesetTemplate":
-		var hostOperatingSystem string
-		if len(hostOS) > 0 {
-			hostOperatingSystem = hostOS[0]
-			switch hostOperatingSystem {
-			case "Linux":
-			case "Windows":
-			default:
-				log.Fatalf("Error: The hostOS must be either 'Linux' or 'Windows'.")
-
-			}
-		} else if len(hostOS) == 0 {
-			log.Fatalf("Error: You must provide the Fileset Tempalte OS type. ")
+		if len(hostOS) == 0 {
+			return "", errors.New("You must provide the Fileset Template OS type.")
+		}
 
+		hostOperatingSystem := hostOS[0]
+		if hostOperatingSystem != "Linux" && hostOperatingSystem != "Windows" {
+			return "", errors.New("The hostOS must be either 'Linux' or 'Windows'.")
 		}
 
 		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/fileset_template?primary_cluster_id=local&operating_system_type=%s&name=%s", hostOperatingSystem, objectName)
+		objectSummaryAPIEndpoint = queryEndpoint("/fileset_template", url.Values{"primary_cluster_id": {"local"}, "operating_system_type": {hostOperatingSystem}, "name": {objectName}})
 	case "managedVolume":
 		objectSummaryAPIVersion = "internal"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/managed_volume?is_relic=false&primary_cluster_id=local&name=%s", objectName)
-	}
-
-	apiRequest := c.Get(objectSummaryAPIVersion, objectSummaryAPIEndpoint).(map[string]interface{})
-	if apiRequest["total"] == 0 {
-		log.Fatalf(fmt.Sprintf("Error: The %s object '%s' was not found on the Rubrik cluster.", objectType, objectName))
-	} else if apiRequest["total"].(float64) > 0 {
-		objectIDs := make([]string, 0)
-		// # Define the "object name" to search for
-		var nameValue string
-		if objectType == "physicalHost" {
-			nameValue = "hostname"
-		} else {
-			nameValue = "name"
+		objectSummaryAPIEndpoint = queryEndpoint("/managed_volume", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {objectName}})
+	case "vcenter":
+		objectSummaryAPIVersion = "v1"
+		objectSummaryAPIEndpoint = "/vmware/vcenter?primary_cluster_id=local"
+	case "mssql":
+		objectSummaryAPIVersion = "v1"
+		objectSummaryAPIEndpoint = queryEndpoint("/mssql/db", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {objectName}})
+	case "oracleDB":
+		objectSummaryAPIVersion = "internal"
+		objectSummaryAPIEndpoint = queryEndpoint("/oracle/db", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {objectName}})
+	}
+
+	apiRequest, err := c.commonAPI("GET", objectSummaryAPIVersion, objectSummaryAPIEndpoint, nil, c.httpTimeout(nil))
+	if err != nil {
+		return "", err
+	}
+
+	objects, err := getSlice(apiRequest, "data")
+	if err != nil {
+		return "", err
+	}
+
+	// # Define the "object name" to search for
+	var nameValue string
+	if objectType == "physicalHost" {
+		nameValue = "hostname"
+	} else {
+		nameValue = "name"
+	}
+
+	objectIDs := make([]string, 0)
+	for _, v := range objects {
+		name, err := getString(v, nameValue)
+		if err != nil {
+			return "", err
+		}
+
+		if name == objectName {
+			objectID, err := getString(v, "id")
+			if err != nil {
+				return "", err
+			}
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	if len(objectIDs) > 1 {
+		return "", fmt.Errorf("Multiple %s objects named '%s' were found on the Rubrik cluster. Unable to return a specific object id.", objectType, objectName)
+	} else if len(objectIDs) == 0 {
+		return "", fmt.Errorf("The %s object '%s' was not found on the Rubrik cluster.", objectType, objectName)
+	}
+
+	return objectIDs[0], nil
+}
+
+// ObjectName returns the name of the object with the provided "objectID" and is the inverse of ObjectID().
+//
+// Valid "objectType" choices are:
+//
+//	vmware, sla, vmwareHost, physicalHost, fileset, filesetTemplate, managedVolume, vcenter, mssql, oracleDB
+func (c *Credentials) ObjectName(objectID, objectType string, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(objectID) == 0 {
+		return "", errors.New("The 'objectID' must not be a blank string.")
+	}
+
+	apiVersion, apiEndpoint, err := objectEndpoint(objectType, objectID)
+	if err != nil {
+		return "", errors.New("The 'objectType' must be 'vmware', 'sla', 'vmwareHost', 'physicalHost', 'fileset', 'filesetTemplate', 'managedVolume', 'vcenter', 'mssql', or 'oracleDB'.")
+	}
+
+	objectSummary, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	if objectType == "physicalHost" {
+		return getString(objectSummary, "hostname")
+	}
+
+	return getString(objectSummary, "name")
+}
+
+// UnmanagedObject contains the details of an object that is no longer protected by the Rubrik cluster but still has retained
+// snapshots, such as a deleted VM (relic). The storage values are in bytes.
+type UnmanagedObject struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	ObjectType          string `json:"objectType"`
+	UnmanagedStatus     string `json:"unmanagedStatus"`
+	SnapshotCount       int    `json:"snapshotCount"`
+	LocalStorageBytes   int64  `json:"localStorage"`
+	ArchiveStorageBytes int64  `json:"archiveStorage"`
+}
+
+// GetUnmanagedObjects returns every unmanaged object of the provided "objectType" along with the number of snapshots it still retains
+// and the storage those snapshots consume.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume, mssql, oracleDB
+func (c *Credentials) GetUnmanagedObjects(objectType string, timeout ...int) ([]UnmanagedObject, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	unmanagedObjectType := map[string]string{
+		"vmware":        "VirtualMachine",
+		"physicalHost":  "LinuxFileset,WindowsFileset",
+		"managedVolume": "ManagedVolume",
+		"mssql":         "MssqlDatabase",
+		"oracleDB":      "OracleDatabase",
+	}
+
+	if _, ok := unmanagedObjectType[objectType]; ok != true {
+		return nil, errors.New("The 'objectType' must be 'vmware', 'physicalHost', 'managedVolume', 'mssql', or 'oracleDB'.")
+	}
+
+	objectSummary, err := c.commonAPI("GET", "internal", fmt.Sprintf("/unmanaged_object?object_type=%s", unmanagedObjectType[objectType]), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	objectData, err := getSlice(objectSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	unmanagedObjects := []UnmanagedObject{}
+	if err := convertResponse(objectData, &unmanagedObjects); err != nil {
+		return nil, fmt.Errorf("Unable to read the unmanaged objects from the Rubrik cluster: %s", err)
+	}
+
+	return unmanagedObjects, nil
+}
+
+// DeleteUnmanagedSnapshots deletes every snapshot retained by the unmanaged object "objectID", as returned by GetUnmanagedObjects(),
+// to reclaim the storage they consume. The snapshots can not be recovered once deleted.
+//
+// The function will return:
+//	The full API response for POST /internal/unmanaged_object/snapshot/bulk_delete
+func (c *Credentials) DeleteUnmanagedSnapshots(objectID string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(objectID) == 0 {
+		return nil, errors.New("The 'objectID' must not be a blank string.")
+	}
+
+	config := map[string]interface{}{}
+	config["objectDefinitions"] = []map[string]string{
+		{"objectId": objectID},
+	}
+
+	return c.commonAPI("POST", "internal", "/unmanaged_object/snapshot/bulk_delete", config, httpTimeout)
+}
+
+// ObjectStorageStats contains the storage, in bytes, consumed by the snapshots of a single object. "LogicalBytes" is the size of the
+// protected data, "IngestedBytes" the data transferred to the Rubrik cluster, and "PhysicalBytes" the storage used exclusively by the
+// object after deduplication and compression. "DataReductionPercent" is the percentage of the logical size saved through data reduction.
+type ObjectStorageStats struct {
+	LogicalBytes         int64   `json:"logicalBytes"`
+	IngestedBytes        int64   `json:"ingestedBytes"`
+	PhysicalBytes        int64   `json:"exclusivePhysicalBytes"`
+	SharedPhysicalBytes  int64   `json:"sharedPhysicalBytes"`
+	IndexStorageBytes    int64   `json:"indexStorageBytes"`
+	DataReductionPercent float64 `json:"-"`
+}
+
+// GetObjectStorageStats returns the logical and physical storage consumed by the snapshots of the provided object along with its data
+// reduction.
+//
+// Valid "objectType" choices are:
+//
+//	vmware
+func (c *Credentials) GetObjectStorageStats(objectName, objectType string, timeout ...int) (*ObjectStorageStats, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if objectType != "vmware" {
+		return nil, errors.New("The 'objectType' must be 'vmware'.")
+	}
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	storageSummary, err := c.commonAPI("GET", "internal", fmt.Sprintf("/stats/per_vm_storage/%s", objectID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var storage ObjectStorageStats
+	if err := convertResponse(storageSummary, &storage); err != nil {
+		return nil, fmt.Errorf("Unable to read the storage stats of '%s': %s", objectName, err)
+	}
+
+	if storage.LogicalBytes > 0 {
+		storage.DataReductionPercent = (1 - float64(storage.PhysicalBytes)/float64(storage.LogicalBytes)) * 100
+	}
+
+	return &storage, nil
+}
+
+// SearchResult contains a single object returned by Search(). "ObjectType" is the object type reported by the Rubrik cluster
+// (ex. VirtualMachine, LinuxHost, Mssql, LinuxFileset).
+type SearchResult struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ObjectType string `json:"objectType"`
+}
+
+// Search returns every object protected by the Rubrik cluster, of any type, whose name contains "name". Use ObjectID() instead when
+// the object type is already known and an exact match is required.
+func (c *Credentials) Search(name string, timeout ...int) ([]SearchResult, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(name) == 0 {
+		return nil, errors.New("The 'name' must not be a blank string.")
+	}
+
+	searchSummary, err := c.commonAPI("GET", "internal", queryEndpoint("/hierarchy/root/descendants", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {name}}), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	searchData, err := getSlice(searchSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	results := []SearchResult{}
+	if err := convertResponse(searchData, &results); err != nil {
+		return nil, fmt.Errorf("Unable to read the search results from the Rubrik cluster: %s", err)
+	}
+
+	return results, nil
+}
+
+// AssignSLA adds the "objectName" to the "slaName". vmware is currently the only supported "objectType". To exclude the object from all SLA assignments
+// use "do not protect" as the "slaName". To assign the selected object to the SLA of the next higher level object, use "clear" as the "slaName".
+//
+// The function will return one of the following:
+//	No change required. The vSphere VM '{objectName}' is already assigned to the '{slaName}' SLA Domain.
+//
+//	The full API response for POST /internal/sla_domain/{slaID}/assign.
+func (c *Credentials) AssignSLA(objectName, objectType, slaName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.assignSLA(objectName, objectType, slaName, "", httpTimeout)
+}
+
+// DoNotProtect excludes the "objectName" from all SLA Domain assignments. vmware is currently the only supported "objectType". When
+// "applyToExistingSnapshots" is true the existing snapshots of the object are also removed from their SLA Domain and kept forever,
+// otherwise they continue to be retained by the SLA Domain they were taken with.
+//
+// The function will return one of the following:
+//	No change required. The vSphere VM '{objectName}' is already assigned to the 'do not protect' SLA Domain.
+//
+//	The full API response for POST /internal/sla_domain/UNPROTECTED/assign.
+func (c *Credentials) DoNotProtect(objectName, objectType string, applyToExistingSnapshots bool, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	existingSnapshotRetention := "RetainSnapshots"
+	if applyToExistingSnapshots {
+		existingSnapshotRetention = "KeepForever"
+	}
+
+	return c.assignSLA(objectName, objectType, "do not protect", existingSnapshotRetention, httpTimeout)
+}
+
+// assignSLA adds the "objectName" to the "slaName". When assigning the "do not protect" SLA Domain the "existingSnapshotRetention"
+// (RetainSnapshots, KeepForever, or ExpireImmediately) controls what happens to the existing snapshots of the object.
+func (c *Credentials) assignSLA(objectName, objectType, slaName, existingSnapshotRetention string, httpTimeout int) (interface{}, error) {
+
+	validObjectType := map[string]bool{
+		"vmware": true,
+	}
+
+	if validObjectType[objectType] == false {
+		return nil, errors.New("The 'objectType' must be 'vmware'.")
+	}
+
+	slaID, err := c.assignmentSLAID(slaName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]interface{}{}
+	switch objectType {
+	case "vmware":
+		vmID, err := c.ObjectID(objectName, "vmware")
+		if err != nil {
+			return nil, err
+		}
+
+		vmSummary, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		var currentSLAID string
+		switch slaID {
+		case "INHERIT":
+			currentSLAID, err = getString(vmSummary, "configuredSlaDomainId")
+		default:
+			currentSLAID, err = getString(vmSummary, "effectiveSlaDomainId")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if slaID == currentSLAID {
+			return fmt.Sprintf("No change required. The vSphere VM '%s' is already assigned to the '%s' SLA Domain.", objectName, slaName), nil
+		}
+
+		config["managedIds"] = []string{vmID}
+	}
+
+	if slaID == "UNPROTECTED" && len(existingSnapshotRetention) != 0 {
+		config["existingSnapshotRetention"] = existingSnapshotRetention
+	}
+
+	return c.commonAPI("POST", "internal", fmt.Sprintf("/sla_domain/%s/assign", slaID), config, httpTimeout)
+}
+
+// SetDefaultSLA assigns the "slaName" to every object that new objects of the provided "objectType" are discovered under, so that
+// newly discovered objects inherit the SLA Domain and are protected automatically. For vmware the SLA Domain is assigned to every
+// vCenter Server added to the Rubrik cluster. Objects with a direct SLA Domain assignment, including those lower in the vCenter
+// hierarchy, keep their current assignment. vmware is currently the only supported "objectType".
+//
+// The function will return one of the following:
+//	No change required. Every vCenter Server is already assigned to the '{slaName}' SLA Domain.
+//
+//	The full API response for POST /internal/sla_domain/{slaID}/assign
+func (c *Credentials) SetDefaultSLA(objectType, slaName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if objectType != "vmware" {
+		return nil, errors.New("The 'objectType' must be 'vmware'.")
+	}
+
+	slaID, err := c.assignmentSLAID(slaName)
+	if err != nil {
+		return nil, err
+	}
+
+	vcenterSummary, err := c.commonAPI("GET", "v1", "/vmware/vcenter?primary_cluster_id=local", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	vcenters, err := getSlice(vcenterSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vcenters) == 0 {
+		return nil, errors.New("No vCenter Servers have been added to the Rubrik cluster.")
+	}
+
+	vcenterIDs := []string{}
+	for _, vcenter := range vcenters {
+		vcenterID, err := getString(vcenter, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		if configuredSLAID, _ := getString(vcenter, "configuredSlaDomainId"); configuredSLAID != slaID {
+			vcenterIDs = append(vcenterIDs, vcenterID)
+		}
+	}
+
+	if len(vcenterIDs) == 0 {
+		return fmt.Sprintf("No change required. Every vCenter Server is already assigned to the '%s' SLA Domain.", slaName), nil
+	}
+
+	config := map[string]interface{}{}
+	config["managedIds"] = vcenterIDs
+
+	return c.commonAPI("POST", "internal", fmt.Sprintf("/sla_domain/%s/assign", slaID), config, httpTimeout)
+}
+
+// VerifySLAAssignment determines if the "objectName" is currently protected by the "expectedSLAName" and returns the name of the SLA
+// Domain that is actually protecting the object. Use "do not protect" as the "expectedSLAName" to verify the object is not protected.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, fileset, managedVolume, mssql, oracleDB
+func (c *Credentials) VerifySLAAssignment(objectName, objectType, expectedSLAName string, timeout ...int) (bool, string, error) {
+
+	slaName, _, err := c.GetEffectiveSLA(objectName, objectType, timeout...)
+	if err != nil {
+		return false, "", err
+	}
+
+	return slaName == expectedSLAName, slaName, nil
+}
+
+// GetEffectiveSLA returns the name of the SLA Domain currently protecting the "objectName" and whether that SLA Domain is inherited
+// from a higher level object, such as a vSphere folder or host, rather than assigned directly to the object. "do not protect" is
+// returned for objects that are not protected.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, fileset, managedVolume, mssql, oracleDB
+func (c *Credentials) GetEffectiveSLA(objectName, objectType string, timeout ...int) (string, bool, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware":        true,
+		"fileset":       true,
+		"managedVolume": true,
+		"mssql":         true,
+		"oracleDB":      true,
+	}
+
+	if validObjectType[objectType] == false {
+		return "", false, errors.New("The 'objectType' must be 'vmware', 'fileset', 'managedVolume', 'mssql', or 'oracleDB'.")
+	}
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return "", false, err
+	}
+
+	apiVersion, apiEndpoint, err := objectEndpoint(objectType, objectID)
+	if err != nil {
+		return "", false, err
+	}
+
+	objectSummary, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, httpTimeout)
+	if err != nil {
+		return "", false, err
+	}
+
+	slaID, err := getString(objectSummary, "effectiveSlaDomainId")
+	if err != nil {
+		return "", false, err
+	}
+
+	// Objects without a direct assignment report INHERIT, or no value at all, as the configured SLA Domain
+	configuredSLAID, _ := getString(objectSummary, "configuredSlaDomainId")
+	inherited := configuredSLAID == "" || configuredSLAID == "INHERIT"
+
+	if slaID == "UNPROTECTED" {
+		return "do not protect", inherited, nil
+	}
+
+	slaName, err := getString(objectSummary, "effectiveSlaDomainName")
+	if err != nil {
+		slaName, err = c.ObjectName(slaID, "sla", httpTimeout)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	return slaName, inherited, nil
+}
+
+// assignmentSLAID returns the ID used to assign the "slaName", including the special "do not protect" and "clear" SLA names.
+func (c *Credentials) assignmentSLAID(slaName string) (string, error) {
+
+	switch slaName {
+	case "do not protect":
+		return "UNPROTECTED", nil
+	case "clear":
+		return "INHERIT", nil
+	}
+
+	return c.ObjectID(slaName, "sla")
+}
+
+// BulkAssignSLA adds every object in "objectNames" to the "slaName" with a single assignment request. vmware is currently the only
+// supported "objectType" and the same special "do not protect" and "clear" SLA names as AssignSLA() are supported. Objects that could
+// not be resolved are returned as errors and left out of the assignment, which is still made for the remaining objects. Unlike
+// AssignSLA(), the objects are assigned even if they are already protected by the "slaName".
+//
+// The function will return:
+//	The full API response for POST /internal/sla_domain/{slaID}/assign and an error for each object that was not assigned
+func (c *Credentials) BulkAssignSLA(objectNames []string, objectType, slaName string, timeout ...int) (interface{}, []error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if objectType != "vmware" {
+		return nil, []error{errors.New("The 'objectType' must be 'vmware'.")}
+	}
+
+	if len(objectNames) == 0 {
+		return nil, []error{errors.New("You must provide at least one object name.")}
+	}
+
+	slaID, err := c.assignmentSLAID(slaName)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var objectErrors []error
+	objectIDs := []string{}
+	for _, objectName := range objectNames {
+		objectID, err := c.ObjectID(objectName, objectType)
+		if err != nil {
+			objectErrors = append(objectErrors, fmt.Errorf("%s: %s", objectName, err))
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	if len(objectIDs) == 0 {
+		return nil, objectErrors
+	}
+
+	config := map[string]interface{}{}
+	config["managedIds"] = objectIDs
+
+	assignSLA, err := c.commonAPI("POST", "internal", fmt.Sprintf("/sla_domain/%s/assign", slaID), config, httpTimeout)
+	if err != nil {
+		return nil, append(objectErrors, err)
+	}
+
+	return assignSLA, objectErrors
+}
+
+// CreateManagedVolume creates a new managed volume with "volumeSize" bytes of capacity and "numChannels" channels. The "exportConfig"
+// defines how the managed volume is exported to application hosts and should be in the following format:
+//
+//	exportConfig := map[string]interface{}{}
+//	exportConfig["shareType"] = "NFS"
+//	exportConfig["hostPatterns"] = []string{"10.0.1.20", "10.0.1.21"}
+//
+// Valid "shareType" choices are:
+//
+//	NFS and SMB
+//
+// The function will return one of the following:
+//	No change required. The Managed Volume '{name}' already exists on the Rubrik cluster.
+//
+//	The full API response for POST /internal/managed_volume
+func (c *Credentials) CreateManagedVolume(name string, volumeSize int64, numChannels int, exportConfig map[string]interface{}, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validShareTypes := map[string]bool{
+		"NFS": true,
+		"SMB": true,
+	}
+
+	if shareType, ok := exportConfig["shareType"]; ok {
+		if shareType, ok := shareType.(string); ok != true || validShareTypes[shareType] == false {
+			return nil, errors.New("The 'shareType' must be 'NFS' or 'SMB'.")
 		}
+	}
+
+	if volumeSize <= 0 {
+		return nil, errors.New("The 'volumeSize' must be greater than 0.")
+	}
+
+	if numChannels <= 0 {
+		return nil, errors.New("The 'numChannels' must be greater than 0.")
+	}
+
+	apiRequest, err := c.commonAPI("GET", "internal", queryEndpoint("/managed_volume", url.Values{"primary_cluster_id": {"local"}, "is_relic": {"false"}, "name": {name}}), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	currentManagedVolumes, ok := apiRequest.(map[string]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the Managed Volumes from the Rubrik cluster.")
+	}
+
+	if data, ok := currentManagedVolumes["data"].([]interface{}); ok {
+		for _, v := range data {
+			if managedVolume, ok := v.(map[string]interface{}); ok && managedVolume["name"] == name {
+				return fmt.Sprintf("No change required. The Managed Volume '%s' already exists on the Rubrik cluster.", name), nil
+			}
+		}
+	}
+
+	config := map[string]interface{}{}
+	config["name"] = name
+	config["volumeSize"] = volumeSize
+	config["numChannels"] = numChannels
+	if len(exportConfig) != 0 {
+		config["exportConfig"] = exportConfig
+	}
+
+	return c.commonAPI("POST", "internal", "/managed_volume", config, httpTimeout)
+}
+
+// DeleteManagedVolume deletes the managed volume "name" from the Rubrik cluster.
+//
+// The function will return:
+//	The full API response for DELETE /internal/managed_volume/{managedVolumeID}
+func (c *Credentials) DeleteManagedVolume(name string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	managedVolumeID, err := c.ObjectID(name, "managedVolume")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.commonAPI("DELETE", "internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), nil, httpTimeout)
+}
+
+// GetManagedVolumeChannels returns the paths, in a {ipAddress}:{mountPoint} format, of the NFS or SMB channels exported by the managed
+// volume "name". A client host mounts the channels to write its backup data to the managed volume after BeginManagedVolumeSnapshot().
+func (c *Credentials) GetManagedVolumeChannels(name string, timeout ...int) ([]string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	managedVolumeID, err := c.ObjectID(name, "managedVolume")
+	if err != nil {
+		return nil, err
+	}
+
+	managedVolumeSummary, err := c.commonAPI("GET", "internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := getSlice(managedVolumeSummary, "mainExport", "channels")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the channels of the Managed Volume '%s'.", name)
+	}
+
+	return channelPaths(channels), nil
+}
+
+// ManagedVolumeExportResult contains the details of an export (live mount) created from a managed volume snapshot. The "ChannelPaths"
+// are in a {ipAddress}:{mountPoint} format and are only populated when the Rubrik cluster returns them with the export request.
+type ManagedVolumeExportResult struct {
+	SnapshotID   string
+	SnapshotDate time.Time
+	JobStatusURL string
+	ChannelPaths []string
+}
+
+// ManagedVolumeExport exports the managed volume snapshot taken closest to the provided "date" and "snapshotTime" so that the hosts
+// matching "hostPatterns" can mount and read the backed up data. The "date" should be in a MM-DD-YYYY format and the "snapshotTime"
+// in a HH:MM AM/PM format (ex. 03:30 PM), both in the time zone of the Rubrik cluster.
+//
+// The function will return:
+//	The ID and date of the exported snapshot, the job status URL, and the channel paths (when available) of the export
+func (c *Credentials) ManagedVolumeExport(name, date, snapshotTime string, hostPatterns []string, timeout ...int) (*ManagedVolumeExportResult, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(hostPatterns) == 0 {
+		return nil, errors.New("You must provide at least one host pattern.")
+	}
+
+	recoveryPoint, err := c.dateTimeConversion(date, snapshotTime, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	managedVolumeID, err := c.ObjectID(name, "managedVolume")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := c.objectSnapshots(managedVolumeID, "managedVolume", httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotID, snapshotDate, err := closestSnapshot(snapshots, recoveryPoint)
+	if err != nil {
+		return nil, fmt.Errorf("The Managed Volume '%s' does not have any snapshots.", name)
+	}
+
+	config := map[string]interface{}{}
+	config["hostPatterns"] = hostPatterns
+
+	export, err := c.commonAPI("POST", "internal", fmt.Sprintf("/managed_volume/snapshot/%s/export", snapshotID), config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	exportStatusURL, err := jobStatusURL(export)
+	if err != nil {
+		return nil, err
+	}
+
+	exportResult := &ManagedVolumeExportResult{
+		SnapshotID:   snapshotID,
+		SnapshotDate: snapshotDate,
+		JobStatusURL: exportStatusURL,
+	}
+	exportDetails, _ := export.(map[string]interface{})
+	if channels, ok := exportDetails["channels"].([]interface{}); ok {
+		exportResult.ChannelPaths = channelPaths(channels)
+	}
+
+	return exportResult, nil
+}
+
+// ManagedVolumeExportSummary contains the details of an existing export (live mount) of a managed volume snapshot.
+type ManagedVolumeExportSummary struct {
+	ID                string
+	SnapshotID        string
+	SnapshotDate      time.Time
+	ManagedVolumeID   string
+	ManagedVolumeName string
+	ChannelPaths      []string
+}
+
+// GetManagedVolumeExports returns all exports (live mounts) that have been created from managed volume snapshots along with the owning
+// managed volume and the date of the exported snapshot.
+func (c *Credentials) GetManagedVolumeExports(timeout ...int) ([]ManagedVolumeExportSummary, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/managed_volume/snapshot/export", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	exportSummaries, _ := apiRequest.(map[string]interface{})
+	exports, ok := exportSummaries["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the Managed Volume exports from the Rubrik cluster.")
+	}
+
+	managedVolumeExports := []ManagedVolumeExportSummary{}
+	for _, v := range exports {
+		export, ok := v.(map[string]interface{})
+		if ok != true {
+			continue
+		}
+
+		exportSummary := ManagedVolumeExportSummary{}
+		exportSummary.ID, _ = export["id"].(string)
+		exportSummary.SnapshotID, _ = export["snapshotId"].(string)
+		exportSummary.ManagedVolumeID, _ = export["sourceManagedVolumeId"].(string)
+		exportSummary.ManagedVolumeName, _ = export["sourceManagedVolumeName"].(string)
+		if channels, ok := export["channels"].([]interface{}); ok {
+			exportSummary.ChannelPaths = channelPaths(channels)
+		}
+
+		// The export summary does not include the snapshot date so look it up from the snapshot itself
+		if len(exportSummary.SnapshotID) != 0 {
+			snapshot, err := c.commonAPI("GET", "internal", fmt.Sprintf("/managed_volume/snapshot/%s", exportSummary.SnapshotID), nil, httpTimeout)
+			if err != nil {
+				return nil, err
+			}
+
+			snapshotDetail, _ := snapshot.(map[string]interface{})
+			if date, ok := snapshotDetail["date"].(string); ok {
+				exportSummary.SnapshotDate, _ = time.Parse(time.RFC3339, date)
+			}
+		}
+
+		managedVolumeExports = append(managedVolumeExports, exportSummary)
+	}
+
+	return managedVolumeExports, nil
+}
+
+// DeleteManagedVolumeExport deletes the managed volume snapshot export (live mount) with the provided "exportID".
+//
+// The function will return:
+//	The full API response for DELETE /internal/managed_volume/snapshot/export/{exportID}
+func (c *Credentials) DeleteManagedVolumeExport(exportID string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(exportID) == 0 {
+		return nil, errors.New("The 'exportID' must not be a blank string.")
+	}
+
+	return c.commonAPI("DELETE", "internal", fmt.Sprintf("/managed_volume/snapshot/export/%s", exportID), nil, httpTimeout)
+}
+
+// dateTimeConversion converts a "date" (MM-DD-YYYY) and "snapshotTime" (HH:MM AM/PM) in the Rubrik cluster's time zone to UTC.
+func (c *Credentials) dateTimeConversion(date, snapshotTime string, timeout int) (time.Time, error) {
+
+	clusterTimezone, err := c.GetClusterTimezone(timeout)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := time.LoadLocation(clusterTimezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Unable to load the Rubrik cluster time zone '%s'.", clusterTimezone)
+	}
+
+	dateTime, err := time.ParseInLocation("01-02-2006 03:04 PM", fmt.Sprintf("%s %s", date, snapshotTime), location)
+	if err != nil {
+		return time.Time{}, errors.New("The date and time should be in a 'MM-DD-YYYY' and 'HH:MM AM/PM' format (ex. 12-31-2018 03:30 PM).")
+	}
+
+	return dateTime.UTC(), nil
+}
+
+// Snapshot contains the details of a single snapshot of a Rubrik protected object.
+type Snapshot struct {
+	ID                     string    `json:"id"`
+	Date                   time.Time `json:"date"`
+	SLAName                string    `json:"slaName"`
+	IsOnDemand             bool      `json:"isOnDemandSnapshot"`
+	CloudState             int       `json:"cloudState"`
+	ArchivalLocationIDs    []string  `json:"archivalLocationIds"`
+	ReplicationLocationIDs []string  `json:"replicationLocationIds"`
+}
+
+// GetSnapshots returns every snapshot of the provided "objectName". The archival and replication location IDs of each snapshot
+// identify where copies of the snapshot are stored in addition to the local Rubrik cluster. For a physicalHost, the snapshots of
+// every fileset assigned to the host are returned.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume
+func (c *Credentials) GetSnapshots(objectName, objectType string, timeout ...int) ([]Snapshot, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware":        true,
+		"physicalHost":  true,
+		"managedVolume": true,
+	}
+
+	if validObjectType[objectType] == false {
+		return nil, errors.New("The 'objectType' must be 'vmware', 'physicalHost', or 'managedVolume'.")
+	}
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := c.objectSnapshots(objectID, objectType, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var objectSnapshots []Snapshot
+	if err := convertResponse(snapshots, &objectSnapshots); err != nil {
+		return nil, fmt.Errorf("Unable to read the snapshots of '%s': %s", objectName, err)
+	}
+
+	return objectSnapshots, nil
+}
+
+// DeleteSnapshot deletes the snapshot with the provided "snapshotID" from every location it is stored. Only on-demand snapshots, or
+// snapshots that are no longer managed by an SLA Domain, can be deleted.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume
+//
+// The function will return the full API response for DELETE /{snapshotEndpoint}/{snapshotID}?location=all
+func (c *Credentials) DeleteSnapshot(objectName, objectType, snapshotID string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiVersion, apiEndpoint, err := snapshotEndpoint(objectType, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := c.objectSnapshot(objectName, objectType, snapshotID, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	return c.commonAPI("DELETE", apiVersion, fmt.Sprintf("%s?location=all", apiEndpoint), nil, httpTimeout)
+}
+
+// SetSnapshotRetention retains the snapshot with the provided "snapshotID" forever when "keepForever" is true. When "keepForever"
+// is false the snapshot is returned to the retention of the SLA Domain currently protecting "objectName".
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume
+//
+// The function will return one of the following:
+//	No change required. The snapshot '{snapshotID}' is already retained forever.
+//
+//	No change required. The snapshot '{snapshotID}' is already retained by the '{slaName}' SLA Domain.
+//
+//	The full API response for POST /internal/unmanaged_object/snapshot/assign_sla
+func (c *Credentials) SetSnapshotRetention(objectName, objectType, snapshotID string, keepForever bool, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if _, _, err := snapshotEndpoint(objectType, snapshotID); err != nil {
+		return nil, err
+	}
+
+	objectID, snapshot, err := c.objectSnapshot(objectName, objectType, snapshotID, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var slaID string
+	if keepForever {
+		if snapshot["slaId"] == "UNPROTECTED" {
+			return fmt.Sprintf("No change required. The snapshot '%s' is already retained forever.", snapshotID), nil
+		}
+		slaID = "UNPROTECTED"
+	} else {
+		slaID, err = c.effectiveSLAID(objectID, objectType, snapshotID, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		if snapshot["slaId"] == slaID {
+			return fmt.Sprintf("No change required. The snapshot '%s' is already retained by the '%s' SLA Domain.", snapshotID, snapshot["slaName"]), nil
+		}
+	}
+
+	config := map[string]interface{}{}
+	config["slaDomainId"] = slaID
+	config["snapshotIds"] = []string{snapshotID}
+
+	return c.commonAPI("POST", "internal", "/unmanaged_object/snapshot/assign_sla", config, httpTimeout)
+}
+
+// SetSnapshotLegalHold places the snapshot with the provided "snapshotID" on legal hold when "enabled" is true or dissolves the legal
+// hold when "enabled" is false. A snapshot on legal hold will not expire until the hold is dissolved.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume
+//
+// The function will return one of the following:
+//	No change required. The snapshot '{snapshotID}' is already on legal hold.
+//
+//	No change required. The snapshot '{snapshotID}' is not on legal hold.
+//
+//	The full API response for POST /internal/legal_hold/snapshot
+//
+//	The full API response for POST /internal/legal_hold/snapshot/dissolve
+func (c *Credentials) SetSnapshotLegalHold(objectName, objectType, snapshotID string, enabled bool, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if _, _, err := snapshotEndpoint(objectType, snapshotID); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := c.objectSnapshot(objectName, objectType, snapshotID, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	apiRequest, err := c.commonAPI("GET", "internal", "/legal_hold/snapshot", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	legalHoldSummary, _ := apiRequest.(map[string]interface{})
+	legalHolds, ok := legalHoldSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the legal holds from the Rubrik cluster.")
+	}
+
+	onLegalHold := false
+	for _, v := range legalHolds {
+		legalHold, _ := v.(map[string]interface{})
+		if legalHold["snapshotId"] == snapshotID {
+			onLegalHold = true
+			break
+		}
+	}
+
+	if enabled && onLegalHold {
+		return fmt.Sprintf("No change required. The snapshot '%s' is already on legal hold.", snapshotID), nil
+	} else if enabled == false && onLegalHold == false {
+		return fmt.Sprintf("No change required. The snapshot '%s' is not on legal hold.", snapshotID), nil
+	}
+
+	config := map[string]interface{}{}
+	config["snapshotIds"] = []string{snapshotID}
+
+	if enabled {
+		return c.commonAPI("POST", "internal", "/legal_hold/snapshot", config, httpTimeout)
+	}
+
+	return c.commonAPI("POST", "internal", "/legal_hold/snapshot/dissolve", config, httpTimeout)
+}
+
+// ExpireSnapshotsBySLA expires every snapshot retained by the "slaName" SLA Domain that was taken more than "olderThanDays" days ago,
+// regardless of the retention configured on the SLA Domain. The snapshots of the virtual machines, SQL Server databases, and managed
+// volumes protected by the SLA Domain, including relics that are no longer present on their source, are expired in batches of up to
+// 100 snapshots per object. An error is returned, before any snapshot of the object is expired, if the date of a snapshot can not be
+// read. The snapshots can not be recovered once expired.
+//
+// The function will return one of the following:
+//	No change required. The '{slaName}' SLA Domain does not retain any snapshots older than {olderThanDays} days.
+//
+//	A list of the full API responses for POST /internal/unmanaged_object/{objectID}/snapshot/bulk_delete
+func (c *Credentials) ExpireSnapshotsBySLA(slaName string, olderThanDays int, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if olderThanDays <= 0 {
+		return nil, errors.New("The 'olderThanDays' must be greater than 0.")
+	}
+
+	slaID, err := c.ObjectID(slaName, "sla")
+	if err != nil {
+		return nil, err
+	}
+
+	protectedObjects := []struct {
+		objectType  string
+		apiVersion  string
+		apiEndpoint string
+	}{
+		{"vmware", "v1", "/vmware/vm"},
+		{"mssql", "v1", "/mssql/db"},
+		{"managedVolume", "internal", "/managed_volume"},
+	}
+
+	expireBefore := time.Now().AddDate(0, 0, -olderThanDays)
+
+	expiredSnapshots := []interface{}{}
+	for _, protectedObject := range protectedObjects {
+		objects, err := c.paginatedData(protectedObject.apiVersion, queryEndpoint(protectedObject.apiEndpoint, url.Values{"effective_sla_domain_id": {slaID}}), httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			objectID, err := getString(object, "id")
+			if err != nil {
+				return nil, err
+			}
+
+			snapshots, err := c.objectSnapshots(objectID, protectedObject.objectType, httpTimeout)
+			if err != nil {
+				return nil, err
+			}
+
+			snapshotIDs := []string{}
+			for _, snapshot := range snapshots {
+				if snapshotSLAID, _ := getString(snapshot, "slaId"); snapshotSLAID != slaID {
+					continue
+				}
+
+				snapshotID, err := getString(snapshot, "id")
+				if err != nil {
+					return nil, err
+				}
+
+				date, _ := getString(snapshot, "date")
+				snapshotDate, err := time.Parse(time.RFC3339, date)
+				if err != nil {
+					return expiredSnapshots, fmt.Errorf("Unable to read the date of the snapshot '%s'.", snapshotID)
+				}
+
+				if snapshotDate.After(expireBefore) {
+					continue
+				}
+				snapshotIDs = append(snapshotIDs, snapshotID)
+			}
+
+			for len(snapshotIDs) > 0 {
+				batchSize := pageSize
+				if len(snapshotIDs) < batchSize {
+					batchSize = len(snapshotIDs)
+				}
+
+				config := map[string]interface{}{}
+				config["snapshotIds"] = snapshotIDs[:batchSize]
+
+				bulkDelete, err := c.commonAPI("POST", "internal", fmt.Sprintf("/unmanaged_object/%s/snapshot/bulk_delete", objectID), config, httpTimeout)
+				if err != nil {
+					return expiredSnapshots, err
+				}
+				expiredSnapshots = append(expiredSnapshots, bulkDelete)
+
+				snapshotIDs = snapshotIDs[batchSize:]
+			}
+		}
+	}
+
+	if len(expiredSnapshots) == 0 {
+		return fmt.Sprintf("No change required. The '%s' SLA Domain does not retain any snapshots older than %d days.", slaName, olderThanDays), nil
+	}
+
+	return expiredSnapshots, nil
+}
+
+// snapshotEndpoint returns the API version and endpoint of the snapshot with the provided "snapshotID".
+func snapshotEndpoint(objectType, snapshotID string) (string, string, error) {
+
+	switch objectType {
+	case "vmware":
+		return "v1", fmt.Sprintf("/vmware/vm/snapshot/%s", snapshotID), nil
+	case "physicalHost":
+		return "v1", fmt.Sprintf("/fileset/snapshot/%s", snapshotID), nil
+	case "managedVolume":
+		return "internal", fmt.Sprintf("/managed_volume/snapshot/%s", snapshotID), nil
+	}
+
+	return "", "", errors.New("The 'objectType' must be 'vmware', 'physicalHost', or 'managedVolume'.")
+}
+
+// objectEndpoint returns the API version and endpoint of the object with the provided "objectID".
+func objectEndpoint(objectType, objectID string) (string, string, error) {
+
+	switch objectType {
+	case "vmware":
+		return "v1", fmt.Sprintf("/vmware/vm/%s", objectID), nil
+	case "sla":
+		return "v1", fmt.Sprintf("/sla_domain/%s", objectID), nil
+	case "vmwareHost":
+		return "v1", fmt.Sprintf("/vmware/host/%s", objectID), nil
+	case "physicalHost":
+		return "v1", fmt.Sprintf("/host/%s", objectID), nil
+	case "fileset":
+		return "v1", fmt.Sprintf("/fileset/%s", objectID), nil
+	case "filesetTemplate":
+		return "v1", fmt.Sprintf("/fileset_template/%s", objectID), nil
+	case "vcenter":
+		return "v1", fmt.Sprintf("/vmware/vcenter/%s", objectID), nil
+	case "managedVolume":
+		return "internal", fmt.Sprintf("/managed_volume/%s", objectID), nil
+	case "mssql":
+		return "v1", fmt.Sprintf("/mssql/db/%s", objectID), nil
+	case "oracleDB":
+		return "internal", fmt.Sprintf("/oracle/db/%s", objectID), nil
+	}
+
+	return "", "", fmt.Errorf("The '%s' object type is not supported.", objectType)
+}
+
+// objectSnapshot validates that the snapshot with the provided "snapshotID" belongs to "objectName" and returns the ID of the
+// object along with the snapshot summary.
+func (c *Credentials) objectSnapshot(objectName, objectType, snapshotID string, timeout int) (string, map[string]interface{}, error) {
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	snapshots, err := c.objectSnapshots(objectID, objectType, timeout)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, v := range snapshots {
+		snapshot, _ := v.(map[string]interface{})
+		if snapshot["id"] == snapshotID {
+			return objectID, snapshot, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("The snapshot '%s' was not found for '%s'.", snapshotID, objectName)
+}
+
+// effectiveSLAID returns the ID of the SLA Domain currently protecting the object that the snapshot "snapshotID" belongs to.
+func (c *Credentials) effectiveSLAID(objectID, objectType, snapshotID string, timeout int) (string, error) {
+
+	var apiVersion, apiEndpoint string
+	var err error
+	switch objectType {
+	case "physicalHost":
+		// Physical host snapshots belong to a fileset rather than the host itself
+		apiRequest, err := c.commonAPI("GET", "v1", fmt.Sprintf("/fileset/snapshot/%s", snapshotID), nil, timeout)
+		if err != nil {
+			return "", err
+		}
+
+		filesetSnapshot, _ := apiRequest.(map[string]interface{})
+		apiVersion, apiEndpoint = "v1", fmt.Sprintf("/fileset/%s", filesetSnapshot["filesetId"])
+	default:
+		apiVersion, apiEndpoint, err = objectEndpoint(objectType, objectID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	apiRequest, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	objectSummary, _ := apiRequest.(map[string]interface{})
+	slaID, ok := objectSummary["effectiveSlaDomainId"].(string)
+	if ok != true {
+		return "", errors.New("Unable to determine the SLA Domain protecting the object.")
+	}
+
+	return slaID, nil
+}
+
+// objectSnapshots returns the snapshots of the object with the provided "objectID".
+func (c *Credentials) objectSnapshots(objectID, objectType string, timeout int) ([]interface{}, error) {
+
+	switch objectType {
+	case "vmware":
+		return c.snapshotData("v1", fmt.Sprintf("/vmware/vm/%s/snapshot", objectID), timeout)
+	case "managedVolume":
+		return c.snapshotData("internal", fmt.Sprintf("/managed_volume/%s/snapshot", objectID), timeout)
+	case "mssql":
+		return c.snapshotData("v1", fmt.Sprintf("/mssql/db/%s/snapshot", objectID), timeout)
+	case "physicalHost":
+		apiRequest, err := c.commonAPI("GET", "v1", fmt.Sprintf("/fileset?host_id=%s&is_relic=false", objectID), nil, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		filesetSummary, _ := apiRequest.(map[string]interface{})
+		filesets, ok := filesetSummary["data"].([]interface{})
+		if ok != true {
+			return nil, errors.New("Unable to read the filesets from the Rubrik cluster.")
+		}
+
+		snapshots := []interface{}{}
+		for _, v := range filesets {
+			fileset, _ := v.(map[string]interface{})
+			filesetDetails, err := c.commonAPI("GET", "v1", fmt.Sprintf("/fileset/%s", fileset["id"]), nil, timeout)
+			if err != nil {
+				return nil, err
+			}
+
+			filesetDetail, _ := filesetDetails.(map[string]interface{})
+			filesetSnapshots, _ := filesetDetail["snapshots"].([]interface{})
+			snapshots = append(snapshots, filesetSnapshots...)
+		}
+
+		return snapshots, nil
+	}
+
+	return nil, fmt.Errorf("Snapshots are not supported for the '%s' object type.", objectType)
+}
+
+// snapshotData returns the "data" of a snapshot listing API endpoint.
+func (c *Credentials) snapshotData(apiVersion, apiEndpoint string, timeout int) ([]interface{}, error) {
+
+	apiRequest, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotSummary, _ := apiRequest.(map[string]interface{})
+	snapshots, ok := snapshotSummary["data"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the snapshots from the Rubrik cluster.")
+	}
+
+	return snapshots, nil
+}
+
+// snapshotTakenSince determines if any of the "snapshots" was taken after "since".
+func snapshotTakenSince(snapshots []interface{}, since time.Time) bool {
+
+	for _, snapshot := range snapshots {
+		date, err := getString(snapshot, "date")
+		if err != nil {
+			continue
+		}
+
+		snapshotDate, err := time.Parse(time.RFC3339, date)
+		if err == nil && snapshotDate.After(since) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closestSnapshot returns the ID and date of the snapshot taken closest to "recoveryPoint".
+func closestSnapshot(snapshots []interface{}, recoveryPoint time.Time) (string, time.Time, error) {
+
+	var snapshotID string
+	var snapshotDate time.Time
+	var smallestDelta time.Duration
+
+	for _, v := range snapshots {
+		snapshot, ok := v.(map[string]interface{})
+		if ok != true {
+			continue
+		}
+
+		date, ok := snapshot["date"].(string)
+		if ok != true {
+			continue
+		}
+
+		currentDate, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			continue
+		}
+
+		delta := recoveryPoint.Sub(currentDate)
+		if delta < 0 {
+			delta = -delta
+		}
+
+		if len(snapshotID) == 0 || delta < smallestDelta {
+			snapshotID = fmt.Sprint(snapshot["id"])
+			snapshotDate = currentDate
+			smallestDelta = delta
+		}
+	}
+
+	if len(snapshotID) == 0 {
+		return "", time.Time{}, errors.New("No snapshots were found.")
+	}
+
+	return snapshotID, snapshotDate, nil
+}
+
+// channelPaths converts the channels of a managed volume export into a list of {ipAddress}:{mountPoint} paths.
+func channelPaths(channels []interface{}) []string {
+
+	var paths []string
+	for _, v := range channels {
+		channel, ok := v.(map[string]interface{})
+		if ok != true {
+			continue
+		}
+
+		paths = append(paths, fmt.Sprintf("%s:%s", channel["ipAddress"], channel["mountPoint"]))
+	}
+
+	return paths
+}
+
+// BeginManagedVolumeSnapshot opens a managed volume for writes. All writes to the managed volume until the snapshot is
+// ended will be part of its snapshot.
+//
+// The function will return one of the following:
+//	No change required. The Managed Volume '{name}' is already in a writeable state.
+//
+//	The full API response for POST /internal/managed_volume/{managedVolumeID}/begin_snapshot
+func (c *Credentials) BeginManagedVolumeSnapshot(name string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	managedVolumeID, err := c.ObjectID(name, "managedVolume")
+	if err != nil {
+		return nil, err
+	}
+
+	managedVolumeSummary, err := c.commonAPI("GET", "internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if isWritable, _ := apiValue(managedVolumeSummary, []string{"isWritable"}); isWritable == true {
+		return fmt.Sprintf("No change required. The Managed Volume '%s' is already in a writeable state.", name), nil
+	}
+
+	config := map[string]string{}
+
+	return c.commonAPI("POST", "internal", fmt.Sprintf("/managed_volume/%s/begin_snapshot", managedVolumeID), config, httpTimeout)
+}
+
+// ManagedVolumeSnapshot opens the managed volume "name" for writes, calls "write" to write the backup data to the managed volume, and
+// then closes the managed volume to create a snapshot retained by the "slaName" SLA Domain (use "current" for the SLA Domain configured
+// on the managed volume). The managed volume is always returned to a read-only state, even when "write" returns an error or panics,
+// so that it is not left open for writes.
+//
+// The function will return:
+//	The full API response for POST /internal/managed_volume/{managedVolumeID}/end_snapshot
+func (c *Credentials) ManagedVolumeSnapshot(name, slaName string, write func() error, timeout ...int) (endSnapshot interface{}, err error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if write == nil {
+		return nil, errors.New("The 'write' function must not be nil.")
+	}
+
+	if _, err := c.BeginManagedVolumeSnapshot(name, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	// The snapshot is ended even if "write" panics so the managed volume is not left open for writes
+	defer func() {
+		var endErr error
+		endSnapshot, endErr = c.EndManagedVolumeSnapshot(name, slaName, httpTimeout)
+		if endErr == nil {
+			return
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%s (the Managed Volume could not be returned to a read-only state: %s)", err, endErr)
+		} else {
+			err = endErr
+		}
+	}()
+
+	if err := write(); err != nil {
+		return nil, fmt.Errorf("Unable to write to the Managed Volume '%s': %s", name, err)
+	}
+
+	return nil, nil
+}
+
+// EndManagedVolumeSnapshot closes a managed volume for writes. A snapshot will be created containing all writes since the last begin snapshot call.
+// Use "current" as the "slaName" to retain the snapshot with the SLA Domain currently configured on the managed volume.
+//
+// The function will return one of the following:
+//	No change required. The Managed Volume '{name}' is already in a read-only state.
+//
+//	The full API response for POST /internal/managed_volume/{managedVolumeID}/end_snapshot
+func (c *Credentials) EndManagedVolumeSnapshot(name, slaName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	managedVolumeID, err := c.ObjectID(name, "managedVolume")
+	if err != nil {
+		return nil, err
+	}
+
+	managedVolumeSummary, err := c.commonAPI("GET", "internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if isWritable, _ := apiValue(managedVolumeSummary, []string{"isWritable"}); isWritable == false {
+		return fmt.Sprintf("No change required. The Managed Volume '%s' is already in a read-only state.", name), nil
+	}
+
+	var slaID string
+	switch slaName {
+	case "current":
+		slaID, err = getString(managedVolumeSummary, "configuredSlaDomainId")
+		if err != nil {
+			return nil, fmt.Errorf("Unable to determine the SLA Domain configured on the Managed Volume '%s'.", name)
+		}
+	default:
+		slaID, err = c.ObjectID(slaName, "sla")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := map[string]interface{}{}
+	config["retentionConfig"] = map[string]interface{}{}
+	config["retentionConfig"].(map[string]interface{})["slaId"] = slaID
+
+	return c.commonAPI("POST", "internal", fmt.Sprintf("/managed_volume/%s/end_snapshot", managedVolumeID), config, httpTimeout)
+}
+
+// GetSLAObjects returns the name and ID of a specific object type.
+func (c *Credentials) GetSLAObjects(slaName, objectType string, timeout ...int) interface{} {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware": true,
+	}
+
+	if validObjectType[objectType] == false {
+		log.Fatalf("Error: The 'objectType' must be 'vmware'")
+	}
+
+	switch objectType {
+	case "vmware":
+		slaID, err := c.ObjectID(slaName, "sla")
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+
+		allVMinSLA := c.Get("v1", fmt.Sprintf("/vmware/vm?effective_sla_domain_id=%s&is_relic=false", slaID), httpTimeout).(map[string]interface{})
+
+		if total, _ := Float64Value(allVMinSLA["total"]); total == 0 {
+			return fmt.Sprintf("The SLA '%s' is currently not protecting any %s objects.", slaName, objectType)
+		}
+
+		vmNameID := map[interface{}]interface{}{}
+		for _, v := range allVMinSLA["data"].([]interface{}) {
+			vmNameID[v.(map[string]interface{})["name"]] = v.(map[string]interface{})["id"]
+		}
+
+		return vmNameID
+
+	}
+
+	return ""
+}
+
+// PauseSnapshot suspends all snapshot activity for the provided object. A fileset "objectName" must be unique across the physical
+// hosts of the Rubrik cluster, otherwise pause the physicalHost instead.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, fileset
+//
+// The function will return one of the following:
+//	No change required. The '{objectName}' '{objectType}' is already paused.
+//
+//	The full API response for PATCH /v1/vmware/vm/{vmID}, /v1/host/{hostID}, or /v1/fileset/{filesetID}
+func (c *Credentials) PauseSnapshot(objectName, objectType string, timeout ...int) interface{} {
+
+	httpTimeout := c.longHTTPTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware":       true,
+		"physicalHost": true,
+		"fileset":      true,
+	}
+
+	if validObjectType[objectType] == false {
+		log.Fatalf("Error: The 'objectType' must be 'vmware', 'physicalHost', or 'fileset'")
+	}
+
+	switch objectType {
+	case "vmware":
+		vmID, err := c.ObjectID(objectName, "vmware")
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+
+		vmSummary := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout).(map[string]interface{})
+
+		if vmSummary["blackoutWindowStatus"].(map[string]interface{})["isSnappableBlackoutActive"].(bool) {
+			return fmt.Sprintf("No change required. The '%s' '%s' is already paused.", objectName, objectType)
+		}
+
+		config := map[string]bool{}
+		config["isVmPaused"] = true
+
+		return c.Patch("v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+
+	case "physicalHost", "fileset":
+		objectEndpoint, isPaused, err := c.pauseState(objectName, objectType, httpTimeout)
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+
+		if isPaused {
+			return fmt.Sprintf("No change required. The '%s' '%s' is already paused.", objectName, objectType)
+		}
+
+		config := map[string]bool{}
+		config["isPaused"] = true
+
+		return c.Patch("v1", objectEndpoint, config, httpTimeout)
+	}
+
+	return ""
+}
+
+// ResumeSnapshot resumes all snapshot activity for the provided object. A fileset "objectName" must be unique across the physical
+// hosts of the Rubrik cluster, otherwise resume the physicalHost instead.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, fileset
+//
+// The function will return one of the following:
+//	No change required. The '{objectName}' '{objectType}' is currently not paused.
+//
+//	The full API response for PATCH /v1/vmware/vm/{vmID}, /v1/host/{hostID}, or /v1/fileset/{filesetID}
+func (c *Credentials) ResumeSnapshot(objectName, objectType string, timeout ...int) interface{} {
+
+	httpTimeout := c.longHTTPTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware":       true,
+		"physicalHost": true,
+		"fileset":      true,
+	}
+
+	if validObjectType[objectType] == false {
+		log.Fatalf("Error: The 'objectType' must be 'vmware', 'physicalHost', or 'fileset'")
+	}
+
+	switch objectType {
+	case "vmware":
+		vmID, err := c.ObjectID(objectName, "vmware")
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+
+		vmSummary := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout).(map[string]interface{})
+
+		if vmSummary["blackoutWindowStatus"].(map[string]interface{})["isSnappableBlackoutActive"].(bool) == false {
+			return fmt.Sprintf("No change required. The '%s' '%s' is currently not paused.", objectName, objectType)
+		}
+
+		config := map[string]bool{}
+		config["isVmPaused"] = false
+
+		return c.Patch("v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+
+	case "physicalHost", "fileset":
+		objectEndpoint, isPaused, err := c.pauseState(objectName, objectType, httpTimeout)
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+
+		if isPaused == false {
+			return fmt.Sprintf("No change required. The '%s' '%s' is currently not paused.", objectName, objectType)
+		}
+
+		config := map[string]bool{}
+		config["isPaused"] = false
+
+		return c.Patch("v1", objectEndpoint, config, httpTimeout)
+	}
+
+	return ""
+}
+
+// pauseState returns the v1 API endpoint of a physicalHost or fileset and whether snapshots of the object are currently paused.
+func (c *Credentials) pauseState(objectName, objectType string, timeout int) (string, bool, error) {
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return "", false, err
+	}
+
+	_, apiEndpoint, err := objectEndpoint(objectType, objectID)
+	if err != nil {
+		return "", false, err
+	}
+
+	objectSummary, err := c.commonAPI("GET", "v1", apiEndpoint, nil, timeout)
+	if err != nil {
+		return "", false, err
+	}
+
+	isPaused, err := apiValue(objectSummary, []string{"isPaused"})
+	if err != nil {
+		return "", false, err
+	}
+
+	paused, ok := isPaused.(bool)
+	if ok != true {
+		return "", false, fmt.Errorf("Unable to determine if the '%s' '%s' is paused.", objectName, objectType)
+	}
+
+	return apiEndpoint, paused, nil
+}
+
+// SetGlobalBlackoutWindow schedules a recurring daily blackout window, during which the Rubrik cluster does not start any new
+// snapshots, between the "startTime" and "endTime". Both times should be in a 24-hour HH:MM format (ex. 22:00) in the provided IANA
+// "timezone" (ex. America/Chicago). A window that ends before it starts continues through midnight.
+//
+// The function will return one of the following:
+//	No change required. The global blackout window is already scheduled from {startTime} to {endTime} {timezone}.
+//
+//	The full API response for PATCH /internal/blackout_window, which contains the current blackout windows
+func (c *Credentials) SetGlobalBlackoutWindow(startTime, endTime, timezone string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	window, err := blackoutWindow(startTime, endTime, timezone)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, v := range apiRequest["data"].([]interface{}) {
-			if v.(interface{}).(map[string]interface{})[nameValue].(string) == objectName {
-				objectIDs = append(objectIDs, v.(interface{}).(map[string]interface{})["id"].(string))
-			}
-		}
+	currentWindows, err := c.commonAPI("GET", "internal", "/blackout_window", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-		if len(objectIDs) > 1 {
-			log.Fatalf(fmt.Sprintf("Error: Multiple %s objects named '%s' were found on the Rubrik cluster. Unable to return a specific object id.", objectType, objectName))
-		} else if len(objectIDs) == 0 {
-			log.Fatalf(fmt.Sprintf("Error: The %s object '%s' was not found on the Rubrik cluster.", objectType, objectName))
-		} else {
-			return objectIDs[0]
-		}
+	if blackoutWindowScheduled(currentWindows, "globalBlackoutWindows", window) {
+		return fmt.Sprintf("No change required. The global blackout window is already scheduled from %s to %s %s.", startTime, endTime, timezone), nil
 	}
 
-	log.Fatalf(fmt.Sprintf("Error: The %s object '%s' was not found on the Rubrik cluster.", objectType, objectName))
-	return ""
+	config := map[string]interface{}{}
+	config["globalBlackoutWindows"] = []map[string]string{window}
 
+	return c.commonAPI("PATCH", "internal", "/blackout_window", config, httpTimeout)
 }
 
-// AssignSLA adds the "objectName" to the "slaName". vmware is currently the only supported "objectType". To exclude the object from all SLA assignments
-// use "do not protect" as the "slaName". To assign the selected object to the SLA of the next higher level object, use "clear" as the "slaName".
+// SetObjectBlackoutWindow schedules a recurring daily blackout window for the provided object, during which the Rubrik cluster does
+// not start any new snapshots of the object, between the "startTime" and "endTime". Both times should be in a 24-hour HH:MM format
+// (ex. 22:00) in the provided IANA "timezone" (ex. America/Chicago). The only "objectType" currently supported is vmware. Use
+// PauseSnapshot() to immediately pause an object instead.
 //
 // The function will return one of the following:
-//	No change required. The vSphere VM '{objectName}' is already assigned to the '{slaName}' SLA Domain.
+//	No change required. The '{objectName}' '{objectType}' blackout window is already scheduled from {startTime} to {endTime} {timezone}.
 //
-//	The full API response for POST /internal/sla_domain/{slaID}/assign.
-func (c *Credentials) AssignSLA(objectName, objectType, slaName string, timeout ...int) interface{} {
+//	The full API response for PUT /internal/vmware/vm/{vmID}/blackout_window, which contains the current blackout windows
+func (c *Credentials) SetObjectBlackoutWindow(objectName, objectType, startTime, endTime, timezone string, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	validObjectType := map[string]bool{
-		"vmware": true,
+	if objectType != "vmware" {
+		return nil, errors.New("The 'objectType' must be 'vmware'.")
 	}
 
-	if validObjectType[objectType] == false {
-		log.Fatalf("Error: The 'objectType' must be 'vmware'.")
+	window, err := blackoutWindow(startTime, endTime, timezone)
+	if err != nil {
+		return nil, err
 	}
 
-	var slaID string
-	switch slaName {
-	case "do not protect":
-		slaID = "UNPROTECTED"
-	case "clear":
-		slaID = "INHERIT"
-	default:
-		slaID = c.ObjectID(slaName, "sla")
+	vmID, err := c.ObjectID(objectName, "vmware")
+	if err != nil {
+		return nil, err
+	}
+
+	currentWindows, err := c.commonAPI("GET", "internal", fmt.Sprintf("/vmware/vm/%s/blackout_window", vmID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if blackoutWindowScheduled(currentWindows, "blackoutWindows", window) {
+		return fmt.Sprintf("No change required. The '%s' '%s' blackout window is already scheduled from %s to %s %s.", objectName, objectType, startTime, endTime, timezone), nil
 	}
 
 	config := map[string]interface{}{}
-	switch objectType {
-	case "vmware":
-		vmID := c.ObjectID(objectName, "vmware")
+	config["blackoutWindows"] = []map[string]string{window}
 
-		vmSummary := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout)
+	return c.commonAPI("PUT", "internal", fmt.Sprintf("/vmware/vm/%s/blackout_window", vmID), config, httpTimeout)
+}
 
-		var currentSLAID string
-		switch slaID {
-		case "INHERIT":
-			currentSLAID = vmSummary.(map[string]interface{})["configuredSlaDomainId"].(string)
-		default:
-			currentSLAID = vmSummary.(map[string]interface{})["effectiveSlaDomainId"].(string)
-		}
+// blackoutWindow validates the "startTime", "endTime", and "timezone" of a recurring blackout window and returns the window in the
+// format expected by the Rubrik cluster.
+func blackoutWindow(startTime, endTime, timezone string) (map[string]string, error) {
 
-		if slaID == currentSLAID {
-			return fmt.Sprintf("No change required. The vSphere VM '%s' is already assigned to the '%s' SLA Domain.", objectName, slaName)
-		}
+	start, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return nil, errors.New("The 'startTime' should be in a 24-hour 'HH:MM' format (ex. 22:00).")
+	}
 
-		config["managedIds"] = []string{vmID}
+	end, err := time.Parse("15:04", endTime)
+	if err != nil {
+		return nil, errors.New("The 'endTime' should be in a 24-hour 'HH:MM' format (ex. 06:00).")
+	}
+
+	if start.Equal(end) {
+		return nil, errors.New("The 'startTime' and 'endTime' must not be the same.")
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil || len(timezone) == 0 {
+		return nil, fmt.Errorf("The 'timezone' '%s' is not a valid IANA time zone (ex. America/Chicago).", timezone)
 	}
 
-	return c.Post("internal", fmt.Sprintf("/sla_domain/%s/assign", slaID), config, httpTimeout)
+	window := map[string]string{}
+	window["startTime"] = startTime
+	window["endTime"] = endTime
+	window["timezone"] = timezone
+
+	return window, nil
 }
 
-// BeginManagedVolumeSnapshot opens a managed volume for writes. All writes to the managed volume until the snapshot is
-// ended will be part of its snapshot.
+// blackoutWindowScheduled determines if the "field" list of the "currentWindows" contains only the provided blackout "window".
+func blackoutWindowScheduled(currentWindows interface{}, field string, window map[string]string) bool {
+
+	windows, err := getSlice(currentWindows, field)
+	if err != nil || len(windows) != 1 {
+		return false
+	}
+
+	for key, value := range window {
+		if currentValue, _ := getString(windows[0], key); currentValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetVMwareSnapshotConsistency sets the consistency level the Rubrik cluster requires for snapshots of the "vmName" VMware virtual
+// machine. Use AUTOMATIC to let the Rubrik cluster take the most consistent snapshot possible.
+//
+// Valid "consistencyLevel" choices are:
+//
+//	AUTOMATIC, CRASH_CONSISTENT, FILE_SYSTEM_CONSISTENT, APP_CONSISTENT
 //
 // The function will return one of the following:
-//	No change required. The Managed Volume '{name}' is already in a writeable state.
+//	No change required. The '{vmName}' VM is already configured with the '{consistencyLevel}' snapshot consistency level.
 //
-//	The full API response for POST /internal/managed_volume/{managedVolumeID}/begin_snapshot
-func (c *Credentials) BeginManagedVolumeSnapshot(name string, timeout ...int) interface{} {
+//	The full API response for PATCH /v1/vmware/vm/{vmID}
+func (c *Credentials) SetVMwareSnapshotConsistency(vmName, consistencyLevel string, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	managedVolumeID := c.ObjectID(name, "managedVolume")
+	// The Rubrik cluster uses UNKNOWN to represent automatic consistency
+	consistencyMandate := map[string]string{
+		"AUTOMATIC":              "UNKNOWN",
+		"CRASH_CONSISTENT":       "CRASH_CONSISTENT",
+		"FILE_SYSTEM_CONSISTENT": "FILE_SYSTEM_CONSISTENT",
+		"APP_CONSISTENT":         "APP_CONSISTENT",
+	}
 
-	managedVolumeSummary := c.Get("internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), httpTimeout)
+	mandate, ok := consistencyMandate[consistencyLevel]
+	if ok != true {
+		return nil, errors.New("The 'consistencyLevel' must be 'AUTOMATIC', 'CRASH_CONSISTENT', 'FILE_SYSTEM_CONSISTENT', or 'APP_CONSISTENT'.")
+	}
 
-	if managedVolumeSummary.(map[string]interface{})["isWritable"].(bool) {
+	vmID, err := c.ObjectID(vmName, "vmware")
+	if err != nil {
+		return nil, err
+	}
 
-		return fmt.Sprintf("No change required. The Managed Volume '%s' is already in a writeable state.", name)
+	vmSummary, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	config := map[string]string{}
+	if currentMandate, _ := getString(vmSummary, "snapshotConsistencyMandate"); currentMandate == mandate {
+		return fmt.Sprintf("No change required. The '%s' VM is already configured with the '%s' snapshot consistency level.", vmName, consistencyLevel), nil
+	}
 
-	return c.Post("internal", fmt.Sprintf("/managed_volume/%s/begin_snapshot", managedVolumeID), config, httpTimeout)
+	config := map[string]string{}
+	config["snapshotConsistencyMandate"] = mandate
 
+	return c.commonAPI("PATCH", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
 }
 
-// EndManagedVolumeSnapshot closes a managed volume for writes. A snapshot will be created containing all writes since the last begin snapshot call.
+// SetVMwarePreBackupScript configures the script located at "scriptPath" on the guest OS of the "vmName" VMware virtual machine
+// to run before each snapshot of the VM. The snapshot is aborted or continues, based on the "failureHandling", when the script fails
+// or does not complete within "scriptTimeout" seconds.
+//
+// Valid "failureHandling" choices are:
+//
+//	abort, continue
 //
 // The function will return one of the following:
-//	No change required. The Managed Volume '{name}' is already in a read-only state.
+//	No change required. The '{vmName}' VM is already configured with the provided pre-backup script.
 //
-//	The full API response for POST /internal/managed_volume/{managedVolumeID}/end_snapshot
-func (c *Credentials) EndManagedVolumeSnapshot(name, slaName string, timeout ...int) interface{} {
+//	The full API response for PATCH /v1/vmware/vm/{vmID}, which contains the updated script configuration
+func (c *Credentials) SetVMwarePreBackupScript(vmName, scriptPath, failureHandling string, scriptTimeout int, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	managedVolumeID := c.ObjectID(name, "managedVolume")
+	if len(scriptPath) == 0 {
+		return nil, errors.New("The 'scriptPath' must not be a blank string.")
+	}
 
-	managedVolumeSummary := c.Get("internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), httpTimeout)
+	if failureHandling != "abort" && failureHandling != "continue" {
+		return nil, errors.New("The 'failureHandling' must be 'abort' or 'continue'.")
+	}
 
-	if managedVolumeSummary.(map[string]interface{})["isWritable"].(bool) == false {
+	if scriptTimeout <= 0 {
+		return nil, errors.New("The 'scriptTimeout' must be greater than 0.")
+	}
 
-		return fmt.Sprintf("No change required. The Managed Volume '%s' is already in a read-only state.", name)
+	vmID, err := c.ObjectID(vmName, "vmware")
+	if err != nil {
+		return nil, err
 	}
 
-	var slaID string
-	switch slaName {
-	case "current":
-		slaID = managedVolumeSummary.(map[string]interface{})["configuredSlaDomainId"].(string)
-	default:
-		slaID = c.ObjectID(slaName, "sla")
+	vmSummary, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	currentPath, _ := getString(vmSummary, "preBackupScript", "scriptPath")
+	currentFailureHandling, _ := getString(vmSummary, "preBackupScript", "failureHandling")
+	currentTimeout, _ := apiValue(vmSummary, []string{"preBackupScript", "timeoutMs"})
+
+	if currentPath == scriptPath && currentFailureHandling == failureHandling && numberEq(currentTimeout, scriptTimeout*1000) {
+		return fmt.Sprintf("No change required. The '%s' VM is already configured with the provided pre-backup script.", vmName), nil
 	}
 
 	config := map[string]interface{}{}
-	config["retentionConfig"] = map[string]interface{}{}
-	config["retentionConfig"].(map[string]interface{})["slaId"] = slaID
+	config["preBackupScript"] = map[string]interface{}{
+		"scriptPath":      scriptPath,
+		"failureHandling": failureHandling,
+		"timeoutMs":       scriptTimeout * 1000,
+	}
 
-	return c.Post("internal", fmt.Sprintf("/managed_volume/%s/end_snapshot", managedVolumeID), config, httpTimeout)
+	return c.commonAPI("PATCH", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+}
+
+// VMwareRestore recovers the "vmName" VMware virtual machine in place, overwriting the original VM with the snapshot taken closest to
+// the provided "date" and "snapshotTime", and returns the job status URL of the recovery. The "date" should be in a MM-DD-YYYY format
+// and the "snapshotTime" in a HH:MM AM/PM format (ex. 03:30 PM), both in the time zone of the Rubrik cluster. Any changes made to the
+// VM since the snapshot was taken are lost, so "force" must be set to true to confirm the overwrite.
+func (c *Credentials) VMwareRestore(vmName, date, snapshotTime string, force bool, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if force == false {
+		return "", fmt.Errorf("The '%s' VM will be overwritten by the restore. Set 'force' to true to confirm.", vmName)
+	}
+
+	recoveryPoint, err := c.dateTimeConversion(date, snapshotTime, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	vmID, err := c.ObjectID(vmName, "vmware")
+	if err != nil {
+		return "", err
+	}
+
+	snapshots, err := c.objectSnapshots(vmID, "vmware", httpTimeout)
+	if err != nil {
+		return "", fmt.Errorf("Unable to read the snapshots of the '%s' VM.", vmName)
+	}
+
+	snapshotID, _, err := closestSnapshot(snapshots, recoveryPoint)
+	if err != nil {
+		return "", fmt.Errorf("The '%s' VM does not have any snapshots.", vmName)
+	}
+
+	restore, err := c.commonAPI("POST", "v1", fmt.Sprintf("/vmware/vm/snapshot/%s/in_place_recovery", snapshotID), map[string]interface{}{}, httpTimeout)
+	if err != nil {
+		return "", err
+	}
 
+	return jobStatusURL(restore)
 }
 
-// GetSLAObjects returns the name and ID of a specific object type.
-func (c *Credentials) GetSLAObjects(slaName, objectType string, timeout ...int) interface{} {
+// RefreshVMwareVM refreshes the metadata of the "vmName" VMware virtual machine from its vCenter without refreshing the entire vCenter.
+// Refresh the VM after it has been relocated, such as a storage vMotion to a new datastore, so that new snapshots use the current
+// VM configuration.
+//
+// The function will return:
+//	The job status URL for the VM refresh
+func (c *Credentials) RefreshVMwareVM(vmName string, timeout ...int) (string, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	validObjectType := map[string]bool{
-		"vmware": true,
+	vmID, err := c.ObjectID(vmName, "vmware")
+	if err != nil {
+		return "", err
 	}
 
-	if validObjectType[objectType] == false {
-		log.Fatalf("Error: The 'objectType' must be 'vmware'")
+	vmSummary, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, httpTimeout)
+	if err != nil {
+		return "", err
 	}
 
-	switch objectType {
-	case "vmware":
-		slaID := c.ObjectID(slaName, "sla")
+	vCenterID, err := getString(vmSummary, "vcenterId")
+	if err != nil {
+		return "", fmt.Errorf("Unable to determine the vCenter of the '%s' VM.", vmName)
+	}
 
-		allVMinSLA := c.Get("v1", fmt.Sprintf("/vmware/vm?effective_sla_domain_id=%s&is_relic=false", slaID), httpTimeout).(map[string]interface{})
+	vmMoid, err := getString(vmSummary, "moid")
+	if err != nil {
+		return "", fmt.Errorf("Unable to determine the managed object ID of the '%s' VM.", vmName)
+	}
 
-		if allVMinSLA["total"].(float64) == 0 {
-			return fmt.Sprintf("The SLA '%s' is currently not protecting any %s objects.", slaName, objectType)
-		}
+	config := map[string]string{}
+	config["vmMoid"] = vmMoid
 
-		vmNameID := map[interface{}]interface{}{}
-		for _, v := range allVMinSLA["data"].([]interface{}) {
-			vmNameID[v.(map[string]interface{})["name"]] = v.(map[string]interface{})["id"]
-		}
+	refresh, err := c.commonAPI("POST", "internal", fmt.Sprintf("/vmware/vcenter/%s/refresh_vm", vCenterID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
 
-		return vmNameID
+	return jobStatusURL(refresh)
+}
+
+// VMSummary contains the protection details of a single VMware virtual machine. "SLAAssignment" is Direct when the SLA Domain is
+// assigned to the VM itself, Derived when it is inherited from a parent object, or Unassigned. "Protected" is false when the VM is not
+// protected by any SLA Domain.
+type VMSummary struct {
+	ID                     string `json:"id"`
+	Name                   string `json:"name"`
+	EffectiveSLADomainID   string `json:"effectiveSlaDomainId"`
+	EffectiveSLADomainName string `json:"effectiveSlaDomainName"`
+	SLAAssignment          string `json:"slaAssignment"`
+	PowerStatus            string `json:"powerStatus"`
+	IsRelic                bool   `json:"isRelic"`
+	Protected              bool   `json:"-"`
+}
 
+// GetAllVMs returns a summary of every VMware virtual machine on the Rubrik cluster along with its SLA Domain and protection status.
+// Set "includeRelics" to true to also return VMs that have been removed from vCenter but still have retained snapshots.
+func (c *Credentials) GetAllVMs(includeRelics bool, timeout ...int) ([]VMSummary, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	query := url.Values{"primary_cluster_id": {"local"}}
+	if includeRelics == false {
+		query.Set("is_relic", "false")
 	}
 
-	return ""
+	vms, err := c.paginatedData("v1", queryEndpoint("/vmware/vm", query), httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	vmSummaries := []VMSummary{}
+	if err := convertResponse(vms, &vmSummaries); err != nil {
+		return nil, fmt.Errorf("Unable to read the VMs from the Rubrik cluster: %s", err)
+	}
+
+	for i, vm := range vmSummaries {
+		vmSummaries[i].Protected = len(vm.EffectiveSLADomainID) != 0 && vm.EffectiveSLADomainID != "UNPROTECTED"
+	}
+
+	return vmSummaries, nil
 }
 
-// PauseSnapshot suspends all snapshot activity for the provided object. The only "objectType" current supported is vmware.
+// ExcludeVMDisks excludes the virtual disks with the provided device keys ("diskKeys") from all future snapshots of the "vmName"
+// VMware virtual machine.
 //
 // The function will return one of the following:
-//	No change required. The '{objectName}' '{objectType}' is already paused.
+//	No change required. The provided virtual disks are already excluded from snapshots of the '{vmName}' VM.
 //
-//	The full API response for POST /internal/vmware/vm/{vmID}
-func (c *Credentials) PauseSnapshot(objectName, objectType string, timeout ...int) interface{} {
+//	The full API response for GET /v1/vmware/vm/{vmID} after the virtual disks have been updated
+func (c *Credentials) ExcludeVMDisks(vmName string, diskKeys []int, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.setVMDiskExclusion(vmName, diskKeys, true, httpTimeout)
+}
+
+// IncludeVMDisks includes the virtual disks with the provided device keys ("diskKeys") in all future snapshots of the "vmName"
+// VMware virtual machine.
+//
+// The function will return one of the following:
+//	No change required. The provided virtual disks are already included in snapshots of the '{vmName}' VM.
+//
+//	The full API response for GET /v1/vmware/vm/{vmID} after the virtual disks have been updated
+func (c *Credentials) IncludeVMDisks(vmName string, diskKeys []int, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.setVMDiskExclusion(vmName, diskKeys, false, httpTimeout)
+}
 
-	httpTimeout := httpTimeout(timeout)
+// setVMDiskExclusion sets the excludeFromSnapshots value of each virtual disk of the VM that matches one of the provided device keys.
+func (c *Credentials) setVMDiskExclusion(vmName string, diskKeys []int, exclude bool, timeout int) (interface{}, error) {
 
-	// Change the default to 180
-	if httpTimeout == 15 {
-		httpTimeout = 180
+	if len(diskKeys) == 0 {
+		return nil, errors.New("You must provide at least one virtual disk device key.")
 	}
 
-	validObjectType := map[string]bool{
-		"vmware": true,
+	vmID, err := c.ObjectID(vmName, "vmware")
+	if err != nil {
+		return nil, err
 	}
 
-	if validObjectType[objectType] == false {
-		log.Fatalf("Error: The 'objectType' must be 'vmware'")
+	apiRequest, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, timeout)
+	if err != nil {
+		return nil, err
 	}
 
-	switch objectType {
-	case "vmware":
-		vmID := c.ObjectID(objectName, "vmware")
+	vmSummary, ok := apiRequest.(map[string]interface{})
+	if ok != true {
+		return nil, fmt.Errorf("Unable to read the summary of the '%s' VM.", vmName)
+	}
 
-		vmSummary := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout).(map[string]interface{})
+	virtualDiskIDs, ok := vmSummary["virtualDiskIds"].([]interface{})
+	if ok != true {
+		return nil, fmt.Errorf("The summary of the '%s' VM does not contain any virtual disks.", vmName)
+	}
 
-		if vmSummary["blackoutWindowStatus"].(map[string]interface{})["isSnappableBlackoutActive"].(bool) {
-			return fmt.Sprintf("No change required. The '%s' '%s' is already paused.", objectName, objectType)
+	requestedDisks := map[int]bool{}
+	for _, diskKey := range diskKeys {
+		requestedDisks[diskKey] = false
+	}
+
+	var disksToUpdate []string
+	for _, virtualDiskID := range virtualDiskIDs {
+		apiRequest, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/virtual_disk/%s", virtualDiskID), nil, timeout)
+		if err != nil {
+			return nil, err
 		}
 
-		config := map[string]bool{}
-		config["isVmPaused"] = true
+		virtualDisk, ok := apiRequest.(map[string]interface{})
+		if ok != true {
+			return nil, fmt.Errorf("Unable to read the virtual disk '%s' of the '%s' VM.", virtualDiskID, vmName)
+		}
 
-		return c.Patch("v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+		deviceKey, err := Int64Value(virtualDisk["deviceKey"])
+		if err != nil {
+			continue
+		}
+
+		if _, ok := requestedDisks[int(deviceKey)]; ok {
+			requestedDisks[int(deviceKey)] = true
+			if virtualDisk["excludeFromSnapshots"] != exclude {
+				disksToUpdate = append(disksToUpdate, fmt.Sprint(virtualDiskID))
+			}
+		}
+	}
+
+	for diskKey, found := range requestedDisks {
+		if found == false {
+			return nil, fmt.Errorf("The '%s' VM does not have a virtual disk with the device key '%d'.", vmName, diskKey)
+		}
+	}
 
+	if len(disksToUpdate) == 0 {
+		if exclude {
+			return fmt.Sprintf("No change required. The provided virtual disks are already excluded from snapshots of the '%s' VM.", vmName), nil
+		}
+		return fmt.Sprintf("No change required. The provided virtual disks are already included in snapshots of the '%s' VM.", vmName), nil
 	}
 
-	return ""
+	config := map[string]bool{}
+	config["excludeFromSnapshots"] = exclude
+
+	for _, virtualDiskID := range disksToUpdate {
+		if _, err := c.commonAPI("PATCH", "v1", fmt.Sprintf("/vmware/vm/virtual_disk/%s", virtualDiskID), config, timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, timeout)
 }
 
-// ResumeSnapshot resumes all snapshot activity for the provided object. The only "objectType" currently supported is vmware.
+// SetVMwareArrayIntegration enables or disables storage array integration for the "vmName" VMware virtual machine. When enabled,
+// the Rubrik cluster uses storage array snapshots to take backups of the virtual machine.
 //
 // The function will return one of the following:
-//	No change required. The '{objectName}' '{objectType}' is currently not paused.
+//	No change required. Array integration is already set to '{enabled}' for the '{vmName}' VM.
 //
-//	The full API response for POST /internal/vmware/vm/{vmID}
-func (c *Credentials) ResumeSnapshot(objectName, objectType string, timeout ...int) interface{} {
+//	The full API response for PATCH /v1/vmware/vm/{vmID}
+func (c *Credentials) SetVMwareArrayIntegration(vmName string, enabled bool, timeout ...int) (interface{}, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	httpTimeout := c.httpTimeout(timeout)
 
-	// Change the default to 180
-	if httpTimeout == 15 {
-		httpTimeout = 180
-	}
+	return c.setVMwareVMFlag(vmName, "isArrayIntegrationEnabled", "Array integration", enabled, httpTimeout)
+}
 
-	validObjectType := map[string]bool{
-		"vmware": true,
-	}
+// SetVMwareCBT enables or disables changed block tracking (CBT) for the "vmName" VMware virtual machine.
+//
+// The function will return one of the following:
+//	No change required. Changed block tracking is already set to '{enabled}' for the '{vmName}' VM.
+//
+//	The full API response for PATCH /v1/vmware/vm/{vmID}
+func (c *Credentials) SetVMwareCBT(vmName string, enabled bool, timeout ...int) (interface{}, error) {
 
-	if validObjectType[objectType] == false {
-		log.Fatalf("Error: The 'objectType' must be 'vmware'")
-	}
+	httpTimeout := c.httpTimeout(timeout)
 
-	switch objectType {
-	case "vmware":
-		vmID := c.ObjectID(objectName, "vmware")
+	return c.setVMwareVMFlag(vmName, "isCbtEnabled", "Changed block tracking", enabled, httpTimeout)
+}
 
-		vmSummary := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout).(map[string]interface{})
+// setVMwareVMFlag updates a boolean "field" on the VMware virtual machine if it does not already match "enabled".
+func (c *Credentials) setVMwareVMFlag(vmName, field, description string, enabled bool, timeout int) (interface{}, error) {
 
-		if vmSummary["blackoutWindowStatus"].(map[string]interface{})["isSnappableBlackoutActive"].(bool) == false {
-			return fmt.Sprintf("No change required. The '%s' '%s' is currently not paused.", objectName, objectType)
-		}
+	vmID, err := c.ObjectID(vmName, "vmware")
+	if err != nil {
+		return nil, err
+	}
 
-		config := map[string]bool{}
-		config["isVmPaused"] = false
+	apiRequest, err := c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, timeout)
+	if err != nil {
+		return nil, err
+	}
 
-		return c.Patch("v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+	vmSummary, ok := apiRequest.(map[string]interface{})
+	if ok != true {
+		return nil, fmt.Errorf("Unable to read the summary of the '%s' VM.", vmName)
+	}
 
+	if currentValue, ok := vmSummary[field].(bool); ok && currentValue == enabled {
+		return fmt.Sprintf("No change required. %s is already set to '%t' for the '%s' VM.", description, enabled, vmName), nil
 	}
 
-	return ""
+	config := map[string]bool{}
+	config[field] = enabled
+
+	return c.commonAPI("PATCH", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, timeout)
 }
 
 // OnDemandSnapshotVM initiates an on-demand snapshot for the "objectName". The only "objectType" currently supported is vmware. To use the currently
@@ -338,43 +2226,124 @@ func (c *Credentials) ResumeSnapshot(objectName, objectType string, timeout ...i
 //
 // The function will return:
 //	The job status URL for the on-demand Snapshot
-func (c *Credentials) OnDemandSnapshotVM(objectName, objectType, slaName string, timeout ...int) string {
+func (c *Credentials) OnDemandSnapshotVM(objectName, objectType, slaName string, timeout ...int) (string, error) {
 
-	httpTimeout := httpTimeout(timeout)
+	return c.OnDemandSnapshotVMWithOptions(objectName, objectType, slaName, OnDemandSnapshotOptions{}, timeout...)
+}
 
-	// Change the default to 180
-	if httpTimeout == 15 {
-		httpTimeout = 180
-	}
+// OnDemandSnapshotOptions contains the optional settings used by OnDemandSnapshotVMWithOptions(). When "Wait" is true the function
+// waits for the snapshot to complete, up to the "JobMaxWait" of the Credentials, and returns the ID of the new snapshot instead of the
+// job status URL. When "SkipIfRecent" is
+// greater than 0, no snapshot is taken if the object already has a snapshot that was taken within that window.
+//
+// Snapshots of a VM that is not powered on can not be application consistent. Set "PoweredOffAction" to "skip" to not take a
+// snapshot of a VM that is not powered on, or to "warn" to take the snapshot and log a warning through the logger provided to
+// SetLogger() (or the standard logger). By default the snapshot is taken without checking the power state of the VM.
+type OnDemandSnapshotOptions struct {
+	Wait             bool
+	SkipIfRecent     time.Duration
+	PoweredOffAction string
+}
+
+// OnDemandSnapshotVMWithOptions initiates an on-demand snapshot for the "objectName" using the provided "options". The only "objectType"
+// currently supported is vmware. To use the currently assigned SLA Domain for the snapshot use "current" for the slaName.
+//
+// The function will return one of the following:
+//	No change required. The '{objectName}' '{objectType}' already has a snapshot taken within the last {options.SkipIfRecent}.
+//
+//	No change required. The '{objectName}' '{objectType}' is not powered on ({powerStatus}) and the snapshot was skipped.
+//
+//	The job status URL for the on-demand Snapshot
+//
+//	The ID of the new snapshot when "options.Wait" is true
+func (c *Credentials) OnDemandSnapshotVMWithOptions(objectName, objectType, slaName string, options OnDemandSnapshotOptions, timeout ...int) (string, error) {
+
+	httpTimeout := c.longHTTPTimeout(timeout)
 
 	validObjectType := map[string]bool{
 		"vmware": true,
 	}
 
 	if validObjectType[objectType] == false {
-		log.Fatalf("Error: The 'objectType' must be 'vmware'")
+		return "", errors.New("The 'objectType' must be 'vmware'.")
 	}
 
-	switch objectType {
-	case "vmware":
-		vmID := c.ObjectID(objectName, "vmware")
+	if options.PoweredOffAction != "" && options.PoweredOffAction != "skip" && options.PoweredOffAction != "warn" {
+		return "", errors.New("The 'PoweredOffAction' option must be 'skip' or 'warn'.")
+	}
 
-		var slaID string
-		switch slaName {
-		case "current":
-			slaID = c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID)).(map[string]interface{})["effectiveSlaDomainId"].(string)
-		default:
-			slaID = c.ObjectID(slaName, "sla")
+	vmID, err := c.ObjectID(objectName, "vmware")
+	if err != nil {
+		return "", err
+	}
+
+	var vmSummary interface{}
+	if slaName == "current" || options.PoweredOffAction != "" {
+		vmSummary, err = c.commonAPI("GET", "v1", fmt.Sprintf("/vmware/vm/%s", vmID), nil, httpTimeout)
+		if err != nil {
+			return "", err
 		}
+	}
 
-		config := map[string]string{}
-		config["slaId"] = slaID
+	if options.PoweredOffAction != "" {
+		powerStatus, err := getString(vmSummary, "powerStatus")
+		if err != nil {
+			return "", err
+		}
 
-		return c.Post("v1", fmt.Sprintf("/vmware/vm/%s/snapshot", vmID), config, httpTimeout).(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string)
+		if powerStatus != "poweredOn" {
+			if options.PoweredOffAction == "skip" {
+				return fmt.Sprintf("No change required. The '%s' '%s' is not powered on (%s) and the snapshot was skipped.", objectName, objectType, powerStatus), nil
+			}
 
+			c.logf("Warning: The '%s' '%s' is not powered on (%s). The on-demand snapshot will be crash consistent.", objectName, objectType, powerStatus)
+		}
 	}
 
-	return ""
+	if options.SkipIfRecent > 0 {
+		snapshots, err := c.objectSnapshots(vmID, objectType, httpTimeout)
+		if err != nil {
+			return "", err
+		}
+
+		if snapshotTakenSince(snapshots, time.Now().Add(-options.SkipIfRecent)) {
+			return fmt.Sprintf("No change required. The '%s' '%s' already has a snapshot taken within the last %s.", objectName, objectType, options.SkipIfRecent), nil
+		}
+	}
+
+	var slaID string
+	switch slaName {
+	case "current":
+		slaID, err = getString(vmSummary, "effectiveSlaDomainId")
+		if err != nil {
+			return "", err
+		}
+	default:
+		slaID, err = c.ObjectID(slaName, "sla")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	config := map[string]string{}
+	config["slaId"] = slaID
+
+	snapshot, err := c.commonAPI("POST", "v1", fmt.Sprintf("/vmware/vm/%s/snapshot", vmID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotStatusURL, err := jobStatusURL(snapshot)
+	if err != nil || options.Wait == false {
+		return snapshotStatusURL, err
+	}
+
+	jobStatus, err := c.WaitForJob(snapshotStatusURL, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return jobResultID(jobStatus)
 }
 
 // OnDemandSnapshotPhysical initiates an on-demand snapshot for a physical host ("hostname"). To use the currently  assigned SLA Domain for the
@@ -386,14 +2355,9 @@ func (c *Credentials) OnDemandSnapshotVM(objectName, objectType, slaName string,
 //
 // The function will return:
 //	The job status URL for the on-demand Snapshot
-func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostOS string, timeout ...int) string {
+func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostOS string, timeout ...int) (string, error) {
 
-	httpTimeout := httpTimeout(timeout)
-
-	// Change the default to 180
-	if httpTimeout == 15 {
-		httpTimeout = 180
-	}
+	httpTimeout := c.longHTTPTimeout(timeout)
 
 	validHostOs := map[string]bool{
 		"Linux":   true,
@@ -401,32 +2365,51 @@ func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostO
 	}
 
 	if validHostOs[hostOS] == false {
-		log.Fatalf("Error: The 'hostOS' must be 'Linux' or 'Windows.")
+		return "", errors.New("The 'hostOS' must be 'Linux' or 'Windows'.")
 	}
 
-	hostID := c.ObjectID(hostName, "physicalHost")
+	hostID, err := c.ObjectID(hostName, "physicalHost")
+	if err != nil {
+		return "", err
+	}
 
-	filesetTemplateID := c.ObjectID(fileset, "filesetTemplate", hostOS)
+	filesetTemplateID, err := c.ObjectID(fileset, "filesetTemplate", hostOS)
+	if err != nil {
+		return "", err
+	}
 
-	filesetSummary := c.Get("v1", fmt.Sprintf("/fileset?primary_cluster_id=local&host_id=%s&is_relic=false&template_id=%s", hostID, filesetTemplateID)).(map[string]interface{})
+	hostFileset, err := c.hostFileset(hostID, filesetTemplateID, httpTimeout)
+	if err != nil {
+		return "", err
+	}
 
-	if filesetSummary["total"] == 0 {
-		log.Fatalf(fmt.Sprintf("Error: The Physical Host '%s' is not assigned to the '%s' Fileset.", hostName, fileset))
+	if hostFileset == nil {
+		return "", fmt.Errorf("The Physical Host '%s' is not assigned to the '%s' Fileset.", hostName, fileset)
 	}
 
-	filesetID := filesetSummary["data"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	filesetID, err := getString(hostFileset, "id")
+	if err != nil {
+		return "", err
+	}
 
 	var slaID string
 	switch slaName {
 	case "current":
-		slaID = filesetSummary["data"].([]interface{})[0].(map[string]interface{})["effectiveSlaDomainId"].(string)
+		slaID, err = getString(hostFileset, "effectiveSlaDomainId")
 	default:
-		slaID = c.ObjectID(slaName, "sla")
-
+		slaID, err = c.ObjectID(slaName, "sla")
+	}
+	if err != nil {
+		return "", err
 	}
 
 	config := map[string]string{}
 	config["slaId"] = slaID
 
-	return c.Post("v1", fmt.Sprintf("/fileset/%s/snapshot", filesetID), config, httpTimeout).(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string)
+	snapshot, err := c.commonAPI("POST", "v1", fmt.Sprintf("/fileset/%s/snapshot", filesetID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return jobStatusURL(snapshot)
 }
@@ -0,0 +1,284 @@
+package rubrikcdm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SetMSSQLLogShipping configures the transaction log backups of the SQL Server database "dbName". The "logBackupFrequency" is the
+// number of seconds between log backups and the "logRetentionHours" is the number of hours the log backups are retained.
+//
+// The function will return one of the following:
+//	No change required. The SQL Server database '{dbName}' is already configured with the provided log backup settings.
+//
+//	The full API response for PATCH /v1/mssql/db/{id}
+func (c *Credentials) SetMSSQLLogShipping(dbName string, logBackupFrequency, logRetentionHours int, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if logBackupFrequency <= 0 {
+		return nil, errors.New("The 'logBackupFrequency' must be greater than 0.")
+	}
+
+	if logRetentionHours <= 0 {
+		return nil, errors.New("The 'logRetentionHours' must be greater than 0.")
+	}
+
+	dbID, err := c.ObjectID(dbName, "mssql")
+	if err != nil {
+		return nil, err
+	}
+
+	dbSummary, err := c.commonAPI("GET", "v1", fmt.Sprintf("/mssql/db/%s", dbID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	currentFrequency, _ := apiValue(dbSummary, []string{"logBackupFrequencyInSeconds"})
+	currentRetention, _ := apiValue(dbSummary, []string{"logRetentionHours"})
+	if numberEq(currentFrequency, logBackupFrequency) && numberEq(currentRetention, logRetentionHours) {
+		return fmt.Sprintf("No change required. The SQL Server database '%s' is already configured with the provided log backup settings.", dbName), nil
+	}
+
+	config := map[string]int{}
+	config["logBackupFrequencyInSeconds"] = logBackupFrequency
+	config["logRetentionHours"] = logRetentionHours
+
+	return c.commonAPI("PATCH", "v1", fmt.Sprintf("/mssql/db/%s", dbID), config, httpTimeout)
+}
+
+// MSSQLInstantRecover recovers the SQL Server database "dbName" in place to the point in time "recoveryPoint" and returns the job
+// status URL of the recovery. The "recoveryPoint" should be in a MM-DD-YYYY HH:MM AM/PM format (ex. 12-31-2018 03:30 PM) in the
+// time zone of the Rubrik cluster and must be covered by the snapshots and log backups of the database.
+func (c *Credentials) MSSQLInstantRecover(dbName, recoveryPoint string, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	recoveryTime, err := c.recoveryPointConversion(recoveryPoint, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	dbID, err := c.ObjectID(dbName, "mssql")
+	if err != nil {
+		return "", err
+	}
+
+	config := map[string]interface{}{}
+	config["recoveryPoint"] = map[string]int64{
+		"timestampMs": recoveryTime.UnixNano() / int64(time.Millisecond),
+	}
+	config["finishRecovery"] = true
+
+	instantRecover, err := c.commonAPI("POST", "v1", fmt.Sprintf("/mssql/db/%s/instant_recover", dbID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return jobStatusURL(instantRecover)
+}
+
+// recoveryPointConversion converts a "recoveryPoint" (MM-DD-YYYY HH:MM AM/PM) in the Rubrik cluster's time zone to UTC.
+func (c *Credentials) recoveryPointConversion(recoveryPoint string, timeout int) (time.Time, error) {
+
+	dateTime := strings.SplitN(strings.TrimSpace(recoveryPoint), " ", 2)
+	if len(dateTime) != 2 {
+		return time.Time{}, errors.New("The 'recoveryPoint' should be in a 'MM-DD-YYYY HH:MM AM/PM' format (ex. 12-31-2018 03:30 PM).")
+	}
+
+	return c.dateTimeConversion(dateTime[0], dateTime[1], timeout)
+}
+
+// MSSQLLiveMountResult contains the details of a live mount of a SQL Server database snapshot. The "MountID" is only populated once
+// the mount job has completed.
+type MSSQLLiveMountResult struct {
+	SnapshotID   string
+	SnapshotDate time.Time
+	JobStatusURL string
+	MountID      string
+}
+
+// MSSQLLiveMount live mounts the snapshot of the SQL Server database "dbName" taken closest to the point in time "recoveryPoint" on the
+// SQL Server instance "targetInstanceName" as the database "mountedDatabaseName". The "recoveryPoint" should be in a MM-DD-YYYY HH:MM AM/PM
+// format (ex. 12-31-2018 03:30 PM) in the time zone of the Rubrik cluster. The function waits for the mount job to complete, up to
+// the "JobMaxWait" of the Credentials. When the mount job fails or does not complete in time, the result is returned along with the
+// error so that the job status URL can still be used to track the mount.
+//
+// The function will return:
+//	The ID and date of the mounted snapshot, the job status URL of the mount, and the ID of the live mount
+func (c *Credentials) MSSQLLiveMount(dbName, recoveryPoint, targetInstanceName, mountedDatabaseName string, timeout ...int) (*MSSQLLiveMountResult, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(mountedDatabaseName) == 0 {
+		return nil, errors.New("The 'mountedDatabaseName' must not be a blank string.")
+	}
+
+	recoveryTime, err := c.recoveryPointConversion(recoveryPoint, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	dbID, err := c.ObjectID(dbName, "mssql")
+	if err != nil {
+		return nil, err
+	}
+
+	targetInstanceID, err := c.namedObjectID("v1", "/mssql/instance?primary_cluster_id=local", targetInstanceName, "SQL Server instance", httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := c.objectSnapshots(dbID, "mssql", httpTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the snapshots of the SQL Server database '%s'.", dbName)
+	}
+
+	snapshotID, snapshotDate, err := closestSnapshot(snapshots, recoveryTime)
+	if err != nil {
+		return nil, fmt.Errorf("The SQL Server database '%s' does not have any snapshots.", dbName)
+	}
+
+	config := map[string]interface{}{}
+	config["recoveryPoint"] = map[string]int64{
+		"timestampMs": snapshotDate.UnixNano() / int64(time.Millisecond),
+	}
+	config["targetInstanceId"] = targetInstanceID
+	config["mountedDatabaseName"] = mountedDatabaseName
+
+	liveMount, err := c.commonAPI("POST", "v1", fmt.Sprintf("/mssql/db/%s/mount", dbID), config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	mountResult := &MSSQLLiveMountResult{
+		SnapshotID:   snapshotID,
+		SnapshotDate: snapshotDate,
+	}
+
+	mountResult.JobStatusURL, err = jobStatusURL(liveMount)
+	if err != nil {
+		return nil, err
+	}
+
+	jobStatus, err := c.WaitForJob(mountResult.JobStatusURL, httpTimeout)
+	if err != nil {
+		return mountResult, err
+	}
+
+	mountResult.MountID, err = jobResultID(jobStatus)
+	if err != nil {
+		return mountResult, err
+	}
+
+	return mountResult, nil
+}
+
+// namedObjectID returns the ID of the single object named "name" in the "data" of the API endpoint. The "description" (ex. SQL Server
+// instance) is used in the returned errors.
+func (c *Credentials) namedObjectID(apiVersion, apiEndpoint, name, description string, timeout int) (string, error) {
+
+	objectSummary, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	objects, err := getSlice(objectSummary, "data")
+	if err != nil {
+		return "", err
+	}
+
+	objectIDs := []string{}
+	for _, object := range objects {
+		if currentName, _ := getString(object, "name"); currentName == name {
+			objectID, err := getString(object, "id")
+			if err != nil {
+				return "", err
+			}
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	if len(objectIDs) > 1 {
+		return "", fmt.Errorf("Multiple %ss named '%s' were found on the Rubrik cluster. Unable to return a specific id.", description, name)
+	} else if len(objectIDs) == 0 {
+		return "", fmt.Errorf("The %s '%s' was not found on the Rubrik cluster.", description, name)
+	}
+
+	return objectIDs[0], nil
+}
+
+// OracleLiveMount live mounts the Oracle database "dbName", recovered to the point in time "recoveryPoint", on the "targetHost" and
+// returns the job status URL of the mount. Set "targetIsRAC" to true when the "targetHost" is the name of an Oracle RAC rather than a
+// single-instance Oracle host. The "recoveryPoint" should be in a MM-DD-YYYY HH:MM AM/PM format (ex. 12-31-2018 03:30 PM) in the time
+// zone of the Rubrik cluster.
+func (c *Credentials) OracleLiveMount(dbName, recoveryPoint, targetHost string, targetIsRAC bool, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	recoveryTime, err := c.recoveryPointConversion(recoveryPoint, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	dbID, err := c.ObjectID(dbName, "oracleDB")
+	if err != nil {
+		return "", err
+	}
+
+	var targetID string
+	if targetIsRAC {
+		targetID, err = c.namedObjectID("internal", queryEndpoint("/oracle/rac", url.Values{"name": {targetHost}}), targetHost, "Oracle RAC", httpTimeout)
+	} else {
+		targetID, err = c.namedObjectID("internal", queryEndpoint("/oracle/host", url.Values{"name": {targetHost}}), targetHost, "Oracle host", httpTimeout)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	config := map[string]interface{}{}
+	config["recoveryPoint"] = map[string]int64{
+		"timestampMs": recoveryTime.UnixNano() / int64(time.Millisecond),
+	}
+	config["targetOracleHostOrRacId"] = targetID
+
+	liveMount, err := c.commonAPI("POST", "internal", fmt.Sprintf("/oracle/db/%s/mount", dbID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return jobStatusURL(liveMount)
+}
+
+// OracleInstantRecover recovers the Oracle database "dbName" in place to the point in time "recoveryPoint" and returns the job status
+// URL of the recovery. The "recoveryPoint" should be in a MM-DD-YYYY HH:MM AM/PM format (ex. 12-31-2018 03:30 PM) in the time zone of
+// the Rubrik cluster.
+func (c *Credentials) OracleInstantRecover(dbName, recoveryPoint string, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	recoveryTime, err := c.recoveryPointConversion(recoveryPoint, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	dbID, err := c.ObjectID(dbName, "oracleDB")
+	if err != nil {
+		return "", err
+	}
+
+	config := map[string]interface{}{}
+	config["recoveryPoint"] = map[string]int64{
+		"timestampMs": recoveryTime.UnixNano() / int64(time.Millisecond),
+	}
+
+	instantRecover, err := c.commonAPI("POST", "internal", fmt.Sprintf("/oracle/db/%s/instant_recover", dbID), config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return jobStatusURL(instantRecover)
+}
@@ -0,0 +1,400 @@
+package rubrikcdm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event contains the details of a single Rubrik event. "Message" is extracted from the event information provided by the
+// Rubrik cluster, "Location" is the location of the event series (ex: the target cluster of a replication), and "Time" is
+// converted to UTC.
+type Event struct {
+	ID            string
+	EventSeriesID string
+	ObjectID      string
+	ObjectName    string
+	ObjectType    string
+	EventType     string
+	EventStatus   string
+	Location      string
+	Message       string
+	Time          time.Time
+}
+
+// validEventType contains the Rubrik event types that can be used to filter events.
+var validEventType = map[string]bool{
+	"Archive":       true,
+	"Backup":        true,
+	"Configuration": true,
+	"Diagnostic":    true,
+	"Discovery":     true,
+	"Instantiate":   true,
+	"Maintenance":   true,
+	"Recovery":      true,
+	"Replication":   true,
+	"Storage":       true,
+	"System":        true,
+}
+
+// GetEventSeries returns the most recent "eventType" events, up to "limit", for the provided "objectName". The events are returned in
+// the order provided by the Rubrik cluster, which is newest first.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume
+//
+// Valid "eventType" choices are:
+//
+//	Archive, Backup, Configuration, Diagnostic, Discovery, Instantiate, Maintenance, Recovery, Replication, Storage, System
+func (c *Credentials) GetEventSeries(objectName, objectType, eventType string, limit int, timeout ...int) ([]Event, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware":        true,
+		"physicalHost":  true,
+		"managedVolume": true,
+	}
+
+	if validObjectType[objectType] == false {
+		return nil, errors.New("The 'objectType' must be 'vmware', 'physicalHost', or 'managedVolume'.")
+	}
+
+	if validEventType[eventType] == false {
+		return nil, fmt.Errorf("'%s' is not a valid 'eventType'.", eventType)
+	}
+
+	if limit <= 0 {
+		return nil, errors.New("The 'limit' must be greater than 0.")
+	}
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	events, _, err := c.latestEvents(fmt.Sprintf("event_type=%s&object_ids=%s&limit=%d", eventType, objectID, limit), httpTimeout)
+
+	return events, err
+}
+
+// GetFailedBackups returns every failed backup event that occurred within the last "sinceHours". Each event contains the name of
+// the affected object and the error message reported by the Rubrik cluster.
+func (c *Credentials) GetFailedBackups(sinceHours int, timeout ...int) ([]Event, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if sinceHours <= 0 {
+		return nil, errors.New("The 'sinceHours' must be greater than 0.")
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(sinceHours) * time.Hour)
+
+	failedBackups := []Event{}
+	query := "event_type=Backup&event_status=Failure&limit=100"
+	for {
+		events, hasMore, err := c.latestEvents(query, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		// Events are returned newest first so stop once the window has been passed
+		for _, event := range events {
+			if event.Time.IsZero() == false && event.Time.Before(since) {
+				return failedBackups, nil
+			}
+			failedBackups = append(failedBackups, event)
+		}
+
+		if hasMore == false || len(events) == 0 {
+			break
+		}
+
+		query = fmt.Sprintf("event_type=Backup&event_status=Failure&limit=100&after_id=%s", events[len(events)-1].ID)
+	}
+
+	return failedBackups, nil
+}
+
+// ActiveBackup contains the progress of a backup that is currently running on the Rubrik cluster. "EstimatedCompletion" is
+// extrapolated from the progress made since "StartTime" and is zero until the backup has reported progress.
+type ActiveBackup struct {
+	EventSeriesID       string
+	ObjectID            string
+	ObjectName          string
+	ObjectType          string
+	ProgressPercent     float64
+	StartTime           time.Time
+	EstimatedCompletion time.Time
+}
+
+// GetActiveBackups returns every backup currently running on the Rubrik cluster along with its progress and estimated completion time.
+func (c *Credentials) GetActiveBackups(timeout ...int) ([]ActiveBackup, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	activeBackups := []ActiveBackup{}
+	query := "event_type=Backup&event_status=Running&limit=100"
+	for {
+		events, hasMore, err := c.latestEvents(query, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			activeBackup, err := c.activeBackup(event, httpTimeout)
+			if err != nil {
+				return nil, err
+			}
+			activeBackups = append(activeBackups, activeBackup)
+		}
+
+		if hasMore == false || len(events) == 0 {
+			break
+		}
+
+		query = fmt.Sprintf("event_type=Backup&event_status=Running&limit=100&after_id=%s", events[len(events)-1].ID)
+	}
+
+	return activeBackups, nil
+}
+
+// activeBackup reads the progress of the event series of a running backup "event".
+func (c *Credentials) activeBackup(event Event, timeout int) (ActiveBackup, error) {
+
+	activeBackup := ActiveBackup{
+		EventSeriesID: event.EventSeriesID,
+		ObjectID:      event.ObjectID,
+		ObjectName:    event.ObjectName,
+		ObjectType:    event.ObjectType,
+	}
+
+	eventSeries, err := c.commonAPI("GET", "internal", fmt.Sprintf("/event_series/%s", event.EventSeriesID), nil, timeout)
+	if err != nil {
+		return activeBackup, err
+	}
+
+	progress, _ := apiValue(eventSeries, []string{"progressPercentage"})
+	activeBackup.ProgressPercent, _ = Float64Value(progress)
+
+	startTime, _ := getString(eventSeries, "startTime")
+	if parsedTime, err := parseEventTime(startTime); err == nil {
+		activeBackup.StartTime = parsedTime.UTC()
+	}
+
+	if activeBackup.StartTime.IsZero() == false && activeBackup.ProgressPercent > 0 && activeBackup.ProgressPercent < 100 {
+		elapsed := time.Since(activeBackup.StartTime)
+		remaining := time.Duration(float64(elapsed) * (100 - activeBackup.ProgressPercent) / activeBackup.ProgressPercent)
+		activeBackup.EstimatedCompletion = time.Now().UTC().Add(remaining)
+	}
+
+	return activeBackup, nil
+}
+
+// latestEvents returns the latest event of each event series matching the provided "query" (ex: event_type=Backup&limit=10) and
+// whether additional events are available.
+func (c *Credentials) latestEvents(query string, timeout int) ([]Event, bool, error) {
+
+	apiRequest, err := c.commonAPI("GET", "v1", fmt.Sprintf("/event/latest?%s", query), nil, timeout)
+	if err != nil {
+		return nil, false, err
+	}
+
+	eventSummary, _ := apiRequest.(map[string]interface{})
+	eventSeries, ok := eventSummary["data"].([]interface{})
+	if ok != true {
+		return nil, false, errors.New("Unable to read the events from the Rubrik cluster.")
+	}
+
+	hasMore, _ := eventSummary["hasMore"].(bool)
+
+	events := []Event{}
+	for _, v := range eventSeries {
+		series, _ := v.(map[string]interface{})
+		latestEvent, ok := series["latestEvent"].(map[string]interface{})
+		if ok != true {
+			continue
+		}
+
+		event := Event{Message: eventMessage(latestEvent["eventInfo"])}
+		event.ID, _ = latestEvent["id"].(string)
+		event.EventSeriesID, _ = latestEvent["eventSeriesId"].(string)
+		event.ObjectID, _ = latestEvent["objectId"].(string)
+		event.ObjectName, _ = latestEvent["objectName"].(string)
+		event.ObjectType, _ = latestEvent["objectType"].(string)
+		event.EventType, _ = latestEvent["eventType"].(string)
+		event.EventStatus, _ = latestEvent["eventStatus"].(string)
+		event.Location, _ = series["location"].(string)
+
+		eventTime, _ := latestEvent["time"].(string)
+		if parsedTime, err := parseEventTime(eventTime); err == nil {
+			event.Time = parsedTime.UTC()
+		}
+
+		events = append(events, event)
+	}
+
+	return events, hasMore, nil
+}
+
+// parseEventTime converts the time of a Rubrik event, which may be in either a RFC3339 or UnixDate format depending on the API
+// endpoint, into a time.Time.
+func parseEventTime(eventTime string) (time.Time, error) {
+
+	parsedTime, err := time.Parse(time.RFC3339, eventTime)
+	if err == nil {
+		return parsedTime, nil
+	}
+
+	return time.Parse("Mon Jan 02 15:04:05 MST 2006", eventTime)
+}
+
+// eventMessage returns the message contained in the JSON encoded "eventInfo" of a Rubrik event.
+func eventMessage(eventInfo interface{}) string {
+
+	eventInfoString, ok := eventInfo.(string)
+	if ok != true {
+		return ""
+	}
+
+	var info map[string]interface{}
+	if err := json.Unmarshal([]byte(eventInfoString), &info); err != nil {
+		return eventInfoString
+	}
+
+	message, _ := info["message"].(string)
+
+	return message
+}
+
+// Webhook contains the details of a webhook that the Rubrik cluster sends event notifications to.
+type Webhook struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	ServerURI  string   `json:"serverUri"`
+	EventTypes []string `json:"eventTypes"`
+	Severities []string `json:"severities"`
+}
+
+// GetWebhooks returns every webhook configured on the Rubrik cluster.
+func (c *Credentials) GetWebhooks(timeout ...int) ([]Webhook, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	webhookSummary, err := c.commonAPI("GET", "internal", "/webhook", nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookData, err := getSlice(webhookSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []Webhook
+	if err := convertResponse(webhookData, &webhooks); err != nil {
+		return nil, fmt.Errorf("Unable to read the webhooks from the Rubrik cluster: %s", err)
+	}
+
+	return webhooks, nil
+}
+
+// CreateWebhook configures the Rubrik cluster to send the "eventTypes" events with one of the provided "severities" to the "serverURI".
+// When a "signingSecret" is provided, the Rubrik cluster signs each notification with an HMAC of the request body so the receiving
+// server can verify its origin. Use a blank string to send unsigned notifications.
+//
+// Valid "eventTypes" choices are:
+//
+//	Archive, Backup, Configuration, Diagnostic, Discovery, Instantiate, Maintenance, Recovery, Replication, Storage, System
+//
+// Valid "severities" choices are:
+//
+//	Critical, Warning, Informational
+//
+// The function will return one of the following:
+//	No change required. The webhook '{name}' is already configured on the Rubrik cluster.
+//
+//	The full API response for POST /internal/webhook
+func (c *Credentials) CreateWebhook(name, serverURI string, eventTypes, severities []string, signingSecret string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if strings.HasPrefix(serverURI, "https://") == false && strings.HasPrefix(serverURI, "http://") == false {
+		return nil, errors.New("The 'serverURI' must be a http:// or https:// URL.")
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, errors.New("You must provide at least one event type.")
+	}
+
+	for _, eventType := range eventTypes {
+		if validEventType[eventType] == false {
+			return nil, fmt.Errorf("'%s' is not a valid 'eventType'.", eventType)
+		}
+	}
+
+	validSeverity := map[string]bool{
+		"Critical":      true,
+		"Warning":       true,
+		"Informational": true,
+	}
+
+	if len(severities) == 0 {
+		return nil, errors.New("You must provide at least one severity.")
+	}
+
+	for _, severity := range severities {
+		if validSeverity[severity] == false {
+			return nil, fmt.Errorf("'%s' is not a valid severity. The severities must be 'Critical', 'Warning', or 'Informational'.", severity)
+		}
+	}
+
+	currentWebhooks, err := c.GetWebhooks(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range currentWebhooks {
+		if webhook.Name == name {
+			return fmt.Sprintf("No change required. The webhook '%s' is already configured on the Rubrik cluster.", name), nil
+		}
+	}
+
+	config := map[string]interface{}{}
+	config["name"] = name
+	config["serverUri"] = serverURI
+	config["eventTypes"] = eventTypes
+	config["severities"] = severities
+	if len(signingSecret) != 0 {
+		config["signingSecret"] = signingSecret
+	}
+
+	return c.commonAPI("POST", "internal", "/webhook", config, httpTimeout)
+}
+
+// DeleteWebhook removes the webhook "name" from the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The webhook '{name}' is not configured on the Rubrik cluster.
+//
+//	The full API response for DELETE /internal/webhook/{id}
+func (c *Credentials) DeleteWebhook(name string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	currentWebhooks, err := c.GetWebhooks(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range currentWebhooks {
+		if webhook.Name == name {
+			return c.commonAPI("DELETE", "internal", fmt.Sprintf("/webhook/%s", webhook.ID), nil, httpTimeout)
+		}
+	}
+
+	return fmt.Sprintf("No change required. The webhook '%s' is not configured on the Rubrik cluster.", name), nil
+}
@@ -1,56 +1,69 @@
 package rubrikcdm_test
 
 import (
+	"fmt"
 	"io/ioutil"
+	"log"
+	"net/http"
 	"os"
+	"os/exec"
+	"time"
 
 	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
 )
 
 func ExampleCredentials_ClusterVersion() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	clusterVersion := rubrik.ClusterVersion()
 }
 
 func ExampleCredentials_BeginManagedVolumeSnapshot() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	mvName := "GoSDK"
 
-	beginMV := rubrik.BeginManagedVolumeSnapshot(mvName)
+	beginMV, err := rubrik.BeginManagedVolumeSnapshot(mvName)
 }
 
 func ExampleCredentials_PauseSnapshot() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vmName := "vm01"
 
 	pauseVM := rubrik.PauseSnapshot(vmName, "vmware")
 }
 
+func ExampleCredentials_PauseSnapshot_physicalHost() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	hostname := "app01.rubrikgo.local"
+
+	pauseHost := rubrik.PauseSnapshot(hostname, "physicalHost")
+}
+
 func ExampleCredentials_OnDemandSnapshotVM() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vmName := "ansible-node01"
 	sla := "current"
 
-	vmSnapshot := rubrik.OnDemandSnapshotVM(vmName, "vmware", sla)
+	vmSnapshot, err := rubrik.OnDemandSnapshotVM(vmName, "vmware", sla)
 }
 
 func ExampleCredentials_OnDemandSnapshotPhysical() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	hostname := "vm01"
 	slaName := "current"
 	fileset := "C_Drive"
 	hostOS := "Windows"
 
-	hostSnapshot := rubrik.OnDemandSnapshotPhysical(hostname, slaName, fileset, hostOS)
+	hostSnapshot, err := rubrik.OnDemandSnapshotPhysical(hostname, slaName, fileset, hostOS)
 }
 
 func ExampleCredentials_ResumeSnapshot() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vmName := "vm01"
 
@@ -58,7 +71,7 @@ func ExampleCredentials_ResumeSnapshot() {
 }
 
 func ExampleCredentials_GetSLAObjects() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	slaName := "Gold"
 
@@ -67,31 +80,31 @@ func ExampleCredentials_GetSLAObjects() {
 }
 
 func ExampleCredentials_EndManagedVolumeSnapshot() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	mvName := "GoSDK"
 	slaName := "Gold"
 
-	endMV := rubrik.EndManagedVolumeSnapshot(mvName, slaName)
+	endMV, err := rubrik.EndManagedVolumeSnapshot(mvName, slaName)
 }
 
 func ExampleCredentials_AssignSLA() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	objectName := "vm01"
 	slaName := "Bronze"
 
-	assignSLA := rubrik.AssignSLA(objectName, "vmware", slaName)
+	assignSLA, err := rubrik.AssignSLA(objectName, "vmware", slaName)
 }
 
 func ExampleCredentials_ConfigureTimezone() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	confTimezone := rubrik.ConfigureTimezone("America/Los_Angeles")
 }
 
 func ExampleCredentials_ConfigureVLAN() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vlanIPs := map[string]string{}
 	vlanIPs["RVM157S018901"] = "192.168.100.100"
@@ -105,8 +118,19 @@ func ExampleCredentials_ConfigureVLAN() {
 	configVLAN := rubrik.ConfigureVLAN(netmask, vlan, vlanIPs)
 }
 
+func ExampleCredentials_AddVCenter() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vCenterHostname := "demogosdk.lab"
+	username := "go"
+	password := "sdk"
+	readcaCertificate, _ := ioutil.ReadFile("ca_cert")
+
+	addVcenter, err := rubrik.AddVCenter(vCenterHostname, username, password, string(readcaCertificate))
+}
+
 func ExampleCredentials_AddvCenter() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vCenterIP := "demogosdk.lab"
 	vCenterUsername := "go"
@@ -117,7 +141,7 @@ func ExampleCredentials_AddvCenter() {
 }
 
 func ExampleCredentials_AddvCenterWithCert() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vCenterIP := "demogosdk.lab"
 	vCenterUsername := "go"
@@ -130,7 +154,7 @@ func ExampleCredentials_AddvCenterWithCert() {
 }
 
 func ExampleCredentials_ConfigureSMTPSettings() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	hostname := "smtp.GOSDK.lab"
 	port := 100
@@ -145,7 +169,7 @@ func ExampleCredentials_ConfigureSMTPSettings() {
 }
 
 func ExampleCredentials_ConfigureSearchDomain() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	searchDomains := []string{"gosdk.lab"}
 
@@ -154,17 +178,17 @@ func ExampleCredentials_ConfigureSearchDomain() {
 }
 
 func ExampleCredentials_ObjectID() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	slaName := "Gold"
 
-	slaID := c.ObjectID(slaName, "sla")
+	slaID, err := rubrik.ObjectID(slaName, "sla")
 }
 
 func ExampleCredentials_Bootstrap() {
 
 	bootstrapNode := "10.77.16.239"
-	rubrik := rubrikcdm.Connect(bootstrapNode, "", "")
+	rubrik, err := rubrikcdm.Connect(bootstrapNode, "", "")
 
 	clusterName := "Go-SDK"
 	adminEmail := "gosdk@rubrik.com"
@@ -188,7 +212,7 @@ func ExampleCredentials_Bootstrap() {
 }
 
 func ExampleCredentials_ConfigureDNSServers() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	dnsServers := []string{"192.21.10.50", "192.21.10.51"}
 
@@ -196,7 +220,7 @@ func ExampleCredentials_ConfigureDNSServers() {
 }
 
 func ExampleCredentials_ConfigureSyslog() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	syslogIP := "192.21.11.29"
 	syslogProtocol := "UDP"
@@ -206,7 +230,7 @@ func ExampleCredentials_ConfigureSyslog() {
 }
 
 func ExampleCredentials_ConfigureNTP() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	ntpServers := []string{"192.21.10.21", "192.21.10.22"}
 
@@ -214,13 +238,13 @@ func ExampleCredentials_ConfigureNTP() {
 }
 
 func ExampleCredentials_ClusterNodeIP() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	clusterVersion := rubrik.ClusterNodeIP()
 }
 
 func ExampleCredentials_EndUserAuthorization() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	vmName := "vm01"
 	endUser := "user01"
@@ -229,19 +253,19 @@ func ExampleCredentials_EndUserAuthorization() {
 }
 
 func ExampleCredentials_ClusterVersionCheck() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	clusterVersion := rubrik.ClusterVersionCheck(4.2)
 }
 
 func ExampleCredentials_Get() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	clusterInfo := rubrik.Get("v1", "/cluster/me")
 }
 
 func ExampleCredentials_Post() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	config := map[string]string{}
 	config["slaId"] = "388a473c-3361-42ab-8f5b-08edb76891f6"
@@ -250,7 +274,7 @@ func ExampleCredentials_Post() {
 }
 
 func ExampleCredentials_Patch() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	config := map[string]string{}
 	config["configuredSlaDomainId"] = "388a473c-3361-42ab-8f5b-08edb76891f6"
@@ -258,14 +282,24 @@ func ExampleCredentials_Patch() {
 	fileset := rubrik.Patch("v1", "/fileset/Fileset:::b95456e2-7d60-4ed0-af88-648516e139a6", config)
 }
 
+func ExampleCredentials_Put() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	config := map[string]string{}
+	config["username"] = "gosdk"
+	config["password"] = "RubrikGoSDK"
+
+	guestCredential := rubrik.Put("internal", "/vmware/guest_credential/d3b5d3b5-9a7b-4d3b-8f5b-08edb76891f6", config)
+}
+
 func ExampleCredentials_Delete() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	deleteSLA := rubrik.Delete("v1", "/sla_domain/388a473c-3361-42ab-8f5b-08edb76891f6")
 }
 
 func ExampleCredentials_AddAWSNativeAccount() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	awsAccountName := "GO SDK Demo" // This is the name that will be displayed in the Rubrik UI
 	awsAccessKey := os.Getenv("AWS_ACCESS_KEY_ID")
@@ -282,8 +316,23 @@ func ExampleCredentials_AddAWSNativeAccount() {
 	addAWSNative := rubrik.AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecretKey, awsRegions, boltConfig)
 }
 
+func ExampleCredentials_AddAWSS3ArchivalLocation() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	name := "AWS:S3:GoSDK"
+	awsBucket := "rubrikgosdk"
+	awsRegion := "us-east-1"
+	awsAccessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	awsSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	storageClass := "STANDARD_IA"
+	readRSAKey, _ := ioutil.ReadFile("rsa_key.pem")
+	rsaKey := string(readRSAKey)
+
+	addArchive, err := rubrik.AddAWSS3ArchivalLocation(name, awsBucket, awsRegion, awsAccessKey, awsSecretKey, storageClass, rsaKey)
+}
+
 func ExampleCredentials_AWSS3CloudOutRSA() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	awsBucket := "rubrikgosdk"
 	storageClass := "standard"
@@ -294,11 +343,11 @@ func ExampleCredentials_AWSS3CloudOutRSA() {
 	readRSAKey, _ := ioutil.ReadFile("rsa_key.pem")
 	rsaKey := string(readRSAKey)
 
-	awsCloudOut := rubrik.AWSS3CloudOut(awsBucket, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey)
+	awsCloudOut := rubrik.AWSS3CloudOutRSA(awsBucket, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey)
 }
 
 func ExampleCredentials_AWSS3CloudOutKMS() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	awsBucket := "rubrikgosdk"
 	storageClass := "standard"
@@ -308,11 +357,11 @@ func ExampleCredentials_AWSS3CloudOutKMS() {
 	awsSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
 	kmsMasterKeyID := os.Getenv("AWS_MASTER_KEY_ID")
 
-	awsCloudOut := rubrik.AWSS3CloudOut(awsBucket, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, kmsMasterKeyID)
+	awsCloudOut := rubrik.AWSS3CloudOutKMS(awsBucket, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, kmsMasterKeyID)
 }
 
 func ExampleCredentials_AWSS3CloudOn() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	archiveName := "AWS:S3:GoSDK"
 	vpcID := "vpc-28e32931"
@@ -323,7 +372,7 @@ func ExampleCredentials_AWSS3CloudOn() {
 }
 
 func ExampleCredentials_AzureCloudOut() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	container := "gosdk"
 	azureAccessKey := os.Getenv("AZURE_ACCESS_KEY")
@@ -337,7 +386,7 @@ func ExampleCredentials_AzureCloudOut() {
 }
 
 func ExampleCredentials_AzureCloudOn() {
-	rubrik := rubrikcdm.ConnectEnv()
+	rubrik, err := rubrikcdm.ConnectEnv()
 
 	archiveName := "Azure:GoSDK"
 	container := "gosdk"
@@ -354,3 +403,1108 @@ func ExampleCredentials_AzureCloudOn() {
 	azureCloudOn := rubrik.AzureCloudOn(archiveName, container, storageAccountName, applicationID, applicationKey, directoryID, region, virtualNetworkID, subnetName, securityGroupID)
 
 }
+
+func ExampleCredentials_GetClusterName() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	clusterName, err := rubrik.GetClusterName()
+}
+
+func ExampleCredentials_SetClusterName() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	clusterName := "GoSDK"
+
+	setClusterName, err := rubrik.SetClusterName(clusterName)
+}
+
+func ExampleCredentials_ClusterStorage() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	clusterStorage, err := rubrik.ClusterStorage()
+}
+
+func ExampleCredentials_ClusterRunway() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	runwayDays, runwayStats, err := rubrik.ClusterRunway()
+}
+
+func ExampleCredentials_SupportTunnelStatus() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	supportTunnel, err := rubrik.SupportTunnelStatus()
+}
+
+func ExampleCredentials_OpenSupportTunnel() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	inactivityTimeout := 14400 // Close the tunnel after 4 hours of inactivity
+
+	openTunnel, err := rubrik.OpenSupportTunnel(inactivityTimeout)
+}
+
+func ExampleCredentials_CloseSupportTunnel() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	closeTunnel, err := rubrik.CloseSupportTunnel()
+}
+
+func ExampleCredentials_RefreshvCenter() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vCenterName := "demogosdk.lab"
+
+	refreshVcenter, err := rubrik.RefreshvCenter(vCenterName)
+}
+
+func ExampleCredentials_GetVMwareGuestCredentials() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	guestCredentials, err := rubrik.GetVMwareGuestCredentials()
+}
+
+func ExampleCredentials_SetVMwareGuestCredential() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	username := "svc-rubrik"
+	password := os.Getenv("GUEST_CREDENTIAL_PASSWORD")
+	domain := "gosdk.lab"
+
+	guestCredential, err := rubrik.SetVMwareGuestCredential(username, password, domain)
+}
+
+func ExampleCredentials_DeleteVMwareGuestCredential() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	username := "svc-rubrik"
+	domain := "gosdk.lab"
+
+	deleteGuestCredential, err := rubrik.DeleteVMwareGuestCredential(username, domain)
+}
+
+func ExampleCredentials_ExcludeVMDisks() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "vm01"
+	diskKeys := []int{2001, 2002}
+
+	excludeDisks, err := rubrik.ExcludeVMDisks(vmName, diskKeys)
+}
+
+func ExampleCredentials_IncludeVMDisks() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "vm01"
+	diskKeys := []int{2001}
+
+	includeDisks, err := rubrik.IncludeVMDisks(vmName, diskKeys)
+}
+
+func ExampleCredentials_SetVMwareArrayIntegration() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "vm01"
+
+	arrayIntegration, err := rubrik.SetVMwareArrayIntegration(vmName, true)
+}
+
+func ExampleCredentials_SetVMwareCBT() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "vm01"
+
+	cbt, err := rubrik.SetVMwareCBT(vmName, true)
+}
+
+func ExampleCredentials_CreateManagedVolume() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	mvName := "GoSDK"
+	volumeSize := int64(1073741824000) // 1000 GB
+	numChannels := 2
+
+	exportConfig := map[string]interface{}{}
+	exportConfig["shareType"] = "NFS"
+	exportConfig["hostPatterns"] = []string{"10.77.16.100"}
+
+	createMV, err := rubrik.CreateManagedVolume(mvName, volumeSize, numChannels, exportConfig)
+}
+
+func ExampleCredentials_DeleteManagedVolume() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	mvName := "GoSDK"
+
+	deleteMV, err := rubrik.DeleteManagedVolume(mvName)
+}
+
+func ExampleCredentials_ManagedVolumeExport() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	mvName := "GoSDK"
+	date := "12-31-2018"
+	time := "03:30 PM"
+	hostPatterns := []string{"10.77.16.100"}
+
+	exportMV, err := rubrik.ManagedVolumeExport(mvName, date, time, hostPatterns)
+}
+
+func ExampleCredentials_GetManagedVolumeExports() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	mvExports, err := rubrik.GetManagedVolumeExports()
+}
+
+func ExampleCredentials_DeleteManagedVolumeExport() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	exportID := "ManagedVolumeSnapshotExport:::5d3b5d3b-9a7b-4d3b-8f5b-08edb76891f6"
+
+	deleteExport, err := rubrik.DeleteManagedVolumeExport(exportID)
+}
+
+func ExampleCredentials_AddAzureArchivalLocation() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	archiveName := "Azure:gosdk"
+	container := "gosdk"
+	storageAccount := "rubrikgosdk"
+	accessKey := os.Getenv("AZURE_ACCESS_KEY")
+	azureRegion := "default"
+	readRSAKey, _ := ioutil.ReadFile("rsa_key.pem")
+
+	options := rubrikcdm.AzureArchivalLocationOptions{
+		RSAKey:        string(readRSAKey),
+		ProxyServer:   "proxy.gosdk.lab",
+		ProxyProtocol: "HTTP",
+		ProxyPort:     3128,
+		ProxyUsername: "go",
+		ProxyPassword: "sdk",
+	}
+
+	azureArchive, err := rubrik.AddAzureArchivalLocation(archiveName, container, storageAccount, accessKey, azureRegion, options)
+}
+
+func ExampleCredentials_AddNFSArchivalLocation() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	archiveName := "NFS:GoSDK"
+	host := "nas.gosdk.lab"
+	exportDir := "/export/rubrik"
+	authType := "SYSTEM"
+
+	nfsArchive, err := rubrik.AddNFSArchivalLocation(archiveName, host, exportDir, authType)
+}
+
+func ExampleCredentials_GetArchivalLocations() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	archiveLocations, err := rubrik.GetArchivalLocations()
+}
+
+func ExampleCredentials_DeleteArchivalLocation() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	archiveName := "AWS:S3:GoSDK"
+
+	deleteArchive, err := rubrik.DeleteArchivalLocation(archiveName)
+	if apiError, ok := err.(*rubrikcdm.APIError); ok {
+		fmt.Println(apiError.StatusCode, apiError.Message)
+	}
+}
+
+func ExampleCredentials_GetReplicationTargets() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	replicationTargets, err := rubrik.GetReplicationTargets()
+}
+
+func ExampleCredentials_AddReplicationTarget() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	targetClusterAddress := "10.77.17.10"
+	username := "admin"
+	password := os.Getenv("TARGET_CLUSTER_PASSWORD")
+
+	replicationTarget, err := rubrik.AddReplicationTarget(targetClusterAddress, username, password)
+}
+
+func ExampleCredentials_GetReplicationStatus() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	replicationStatus, err := rubrik.GetReplicationStatus()
+}
+
+func ExampleCredentials_CreateUser() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	username := "svc-automation"
+	password := "RubrikGoRubrikGo"
+	role := "admin"
+
+	createUser, err := rubrik.CreateUser(username, password, role)
+}
+
+func ExampleCredentials_DeleteUser() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	username := "svc-automation"
+
+	deleteUser, err := rubrik.DeleteUser(username)
+}
+
+func ExampleCredentials_AssignUserRole() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	username := "svc-automation"
+	role := "end_user"
+	objectType := "sla"
+	objects := []string{"Gold", "Silver"}
+
+	assignRole, err := rubrik.AssignUserRole(username, role, objectType, objects)
+}
+
+func ExampleCredentials_AddLDAPService() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	name := "rubrikgo.local"
+	baseDN := "DC=rubrikgo,DC=local"
+	bindUserName := "CN=svc-rubrik,OU=Service Accounts,DC=rubrikgo,DC=local"
+	bindUserPassword := "RubrikGoRubrikGo"
+	serverHosts := []string{"ldap://dc01.rubrikgo.local", "ldap://dc02.rubrikgo.local"}
+	advancedOptions := map[string]interface{}{
+		"userSearchFilter": "(objectClass=user)",
+	}
+
+	addLDAP, err := rubrik.AddLDAPService(name, baseDN, bindUserName, bindUserPassword, serverHosts, advancedOptions)
+}
+
+func ExampleCredentials_GenerateAPIToken() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	expirationMinutes := 60
+	tag := "nightly-automation"
+
+	apiToken, err := rubrik.GenerateAPIToken(expirationMinutes, tag)
+}
+
+func ExampleCredentials_RevokeAPIToken() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	tokenID := "me"
+
+	revokeToken, err := rubrik.RevokeAPIToken(tokenID)
+}
+
+func ExampleConnectAPIToken() {
+	rubrik := rubrikcdm.ConnectAPIToken(os.Getenv("rubrik_cdm_node_ip"), os.Getenv("rubrik_cdm_token"))
+
+	clusterVersion := rubrik.ClusterVersion()
+}
+
+func ExampleCredentials_SetLogger() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	rubrik.SetLogger(log.New(os.Stderr, "rubrikcdm: ", log.LstdFlags))
+
+	clusterVersion := rubrik.ClusterVersion()
+}
+
+func ExampleCredentials_afterResponse() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	rubrik.BeforeRequest = func(request *http.Request) {
+		request.Header.Set("X-Request-Source", "nightly-automation")
+	}
+	rubrik.AfterResponse = func(response *http.Response, duration time.Duration) {
+		fmt.Printf("%s %s returned %d in %s\n", response.Request.Method, response.Request.URL.Path, response.StatusCode, duration)
+	}
+
+	clusterVersion := rubrik.ClusterVersion()
+}
+
+func ExampleRequestCounter() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	requestCounter := &rubrikcdm.RequestCounter{}
+	rubrik.Metrics = requestCounter
+
+	clusterVersion := rubrik.ClusterVersion()
+
+	for metric, stats := range requestCounter.Stats() {
+		fmt.Printf("%s %s %s: %d calls, %s\n", metric.Method, metric.Endpoint, metric.StatusClass, stats.Count, stats.TotalDuration)
+	}
+}
+
+func ExampleCredentials_GetEventSeries() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	objectType := "vmware"
+	eventType := "Backup"
+	limit := 10
+
+	events, err := rubrik.GetEventSeries(objectName, objectType, eventType, limit)
+}
+
+func ExampleCredentials_GetFailedBackups() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	sinceHours := 12
+
+	failedBackups, err := rubrik.GetFailedBackups(sinceHours)
+}
+
+func ExampleCredentials_GetComplianceReport() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	reportName := "SLA Compliance Summary"
+
+	complianceReport, err := rubrik.GetComplianceReport(reportName)
+}
+
+func ExampleCredentials_ExportReportCSV() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	reportName := "SLA Compliance Summary"
+	filePath := "/tmp/sla-compliance.csv"
+
+	err = rubrik.ExportReportCSV(reportName, filePath)
+}
+
+func ExampleCredentials_GetProtectionTasks() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	objectType := "vmware"
+	limit := 10
+
+	protectionTasks, err := rubrik.GetProtectionTasks(objectName, objectType, limit)
+}
+
+func ExampleCredentials_GetSnapshots() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	objectType := "vmware"
+
+	snapshots, err := rubrik.GetSnapshots(objectName, objectType)
+}
+
+func ExampleCredentials_DeleteSnapshot() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	objectType := "vmware"
+	snapshotID := "2bd3a5e9-2ba5-4a5e-9a25-4d3b1c2d0f5b"
+
+	deleteSnapshot, err := rubrik.DeleteSnapshot(objectName, objectType, snapshotID)
+}
+
+func ExampleCredentials_SetSnapshotRetention() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	objectType := "vmware"
+	snapshotID := "2bd3a5e9-2ba5-4a5e-9a25-4d3b1c2d0f5b"
+	keepForever := true
+
+	snapshotRetention, err := rubrik.SetSnapshotRetention(objectName, objectType, snapshotID, keepForever)
+}
+
+func ExampleCredentials_SetSnapshotLegalHold() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	objectType := "vmware"
+	snapshotID := "2bd3a5e9-2ba5-4a5e-9a25-4d3b1c2d0f5b"
+	enabled := true
+
+	legalHold, err := rubrik.SetSnapshotLegalHold(objectName, objectType, snapshotID, enabled)
+}
+
+func ExampleCredentials_SetReplicationThrottle() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	enabled := true
+	mbps := 500
+
+	replicationThrottle, err := rubrik.SetReplicationThrottle(enabled, mbps)
+}
+
+func ExampleCredentials_GetReplicationThrottle() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	replicationThrottle, err := rubrik.GetReplicationThrottle()
+}
+
+func ExampleCredentials_SetArchivalThrottle() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	enabled := true
+	mbps := 250
+
+	archivalThrottle, err := rubrik.SetArchivalThrottle(enabled, mbps)
+}
+
+func ExampleCredentials_GetArchivalThrottle() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	archivalThrottle, err := rubrik.GetArchivalThrottle()
+}
+
+func ExampleCredentials_ConfigureClusterProxy() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	host := "proxy.rubrikgo.local"
+	port := 3128
+	username := "svc-proxy"
+	password := "RubrikGoRubrikGo"
+	protocol := "HTTP"
+
+	clusterProxy, err := rubrik.ConfigureClusterProxy(host, port, username, password, protocol)
+}
+
+func ExampleCredentials_GetClusterProxy() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	clusterProxy, err := rubrik.GetClusterProxy()
+}
+
+func ExampleCredentials_DeleteClusterProxy() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	deleteProxy, err := rubrik.DeleteClusterProxy()
+}
+
+func ExampleCredentials_GetClusterTimezone() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	clusterTimezone, err := rubrik.GetClusterTimezone()
+}
+
+func ExampleCredentials_SetClusterTimezone() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	setTimezone, err := rubrik.SetClusterTimezone("America/Chicago")
+}
+
+func ExampleCredentials_SetClusterVIP() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vips := []string{"192.168.1.110", "192.168.1.111"}
+
+	clusterVIP, err := rubrik.SetClusterVIP(vips)
+}
+
+func ExampleCredentials_GetClusterVIP() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	clusterVIP, err := rubrik.GetClusterVIP()
+}
+
+func ExampleCredentials_RegisterWithPolaris() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	polarisAccount := "rubrikgo"
+	clientID := os.Getenv("polaris_client_id")
+	clientSecret := os.Getenv("polaris_client_secret")
+
+	polarisRegistration, err := rubrik.RegisterWithPolaris(polarisAccount, clientID, clientSecret)
+}
+
+func ExampleCredentials_defaultTimeout() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	// Use a 60 second timeout for every call that does not provide its own timeout
+	rubrik.DefaultTimeout = 60
+
+	clusterVersion := rubrik.ClusterVersion()
+}
+
+func ExampleCredentials_OnDemandSnapshotVMWithOptions() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "ansible-node01"
+	sla := "current"
+	options := rubrikcdm.OnDemandSnapshotOptions{
+		Wait:             true,
+		SkipIfRecent:     30 * time.Minute,
+		PoweredOffAction: "skip",
+	}
+
+	snapshotID, err := rubrik.OnDemandSnapshotVMWithOptions(vmName, "vmware", sla, options)
+}
+
+func ExampleCredentials_WaitForJob() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmSnapshot, err := rubrik.OnDemandSnapshotVM("ansible-node01", "vmware", "current")
+
+	jobStatus, err := rubrik.WaitForJob(vmSnapshot)
+}
+
+func ExampleBuildSLAFrequencies() {
+	frequencies, err := rubrikcdm.BuildSLAFrequencies("v2",
+		rubrikcdm.SLAFrequency{TimeUnit: "Hourly", Frequency: 4, Retention: 24},
+		rubrikcdm.SLAFrequency{TimeUnit: "Daily", Frequency: 1, Retention: 30},
+		rubrikcdm.SLAFrequency{TimeUnit: "Monthly", Frequency: 1, Retention: 12},
+	)
+}
+
+func ExampleCredentials_CreateSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	name := "Gold"
+	frequencies := []rubrikcdm.SLAFrequency{
+		{TimeUnit: "Hourly", Frequency: 4, Retention: 24},
+		{TimeUnit: "Daily", Frequency: 1, Retention: 30},
+	}
+
+	createSLA, err := rubrik.CreateSLA(name, frequencies)
+}
+
+func ExampleCredentials_DoNotProtect() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectName := "ansible-node01"
+	applyToExistingSnapshots := false
+
+	doNotProtect, err := rubrik.DoNotProtect(objectName, "vmware", applyToExistingSnapshots)
+}
+
+func ExampleCredentials_UpdateFilesetTemplate() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	name := "Application Data"
+	includes := []string{"/opt/app/data", "/var/log/app"}
+	excludes := []string{"*.tmp"}
+	exceptions := []string{}
+	operatingSystemType := "Linux"
+
+	updateFileset, err := rubrik.UpdateFilesetTemplate(name, includes, excludes, exceptions, operatingSystemType)
+}
+
+func ExampleCredentials_SetFilesetPrePostScripts() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	filesetTemplateName := "Database Dumps"
+	preBackupScript := "/opt/scripts/quiesce.sh"
+	postBackupScript := "/opt/scripts/unquiesce.sh"
+	errorHandling := "abort"
+	operatingSystemType := "Linux"
+
+	setScripts, err := rubrik.SetFilesetPrePostScripts(filesetTemplateName, preBackupScript, postBackupScript, errorHandling, operatingSystemType)
+}
+
+func ExampleCredentials_AssignFilesetToHost() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	hostName := "app01.rubrikgo.local"
+	filesetTemplateName := "Application Data"
+	hostOS := "Linux"
+
+	filesetID, err := rubrik.AssignFilesetToHost(hostName, filesetTemplateName, hostOS)
+}
+
+func ExampleCredentials_AddNASHost() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	hostname := "isilon01.rubrikgo.local"
+	credentials := map[string]string{}
+	credentials["username"] = "svc-rubrik"
+	credentials["password"] = "RubrikGoRubrikGo"
+	credentials["domain"] = "rubrikgo.local"
+
+	addNASHost, err := rubrik.AddNASHost(hostname, credentials)
+}
+
+func ExampleCredentials_AddNASShare() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	nasHostName := "isilon01.rubrikgo.local"
+	shareType := "SMB"
+	exportPoint := "finance"
+
+	addNASShare, err := rubrik.AddNASShare(nasHostName, shareType, exportPoint, nil)
+}
+
+func ExampleCredentials_SetMSSQLLogShipping() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	dbName := "AdventureWorks"
+	logBackupFrequency := 900
+	logRetentionHours := 168
+
+	logShipping, err := rubrik.SetMSSQLLogShipping(dbName, logBackupFrequency, logRetentionHours)
+}
+
+func ExampleCredentials_MSSQLInstantRecover() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	dbName := "AdventureWorks"
+	recoveryPoint := "12-31-2018 03:30 PM"
+
+	recoverJobStatusURL, err := rubrik.MSSQLInstantRecover(dbName, recoveryPoint)
+}
+
+func ExampleCredentials_MSSQLLiveMount() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	dbName := "AdventureWorks"
+	recoveryPoint := "12-31-2018 03:30 PM"
+	targetInstanceName := "MSSQLSERVER"
+	mountedDatabaseName := "AdventureWorks_Dev"
+
+	liveMount, err := rubrik.MSSQLLiveMount(dbName, recoveryPoint, targetInstanceName, mountedDatabaseName)
+
+	fmt.Println(liveMount.MountID)
+}
+
+func ExampleCredentials_OracleLiveMount() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	dbName := "ORCL"
+	recoveryPoint := "12-31-2018 03:30 PM"
+	targetHost := "oracle-dev01.rubrikgo.local"
+	targetIsRAC := false
+
+	mountJobStatusURL, err := rubrik.OracleLiveMount(dbName, recoveryPoint, targetHost, targetIsRAC)
+}
+
+func ExampleCredentials_OracleInstantRecover() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	dbName := "ORCL"
+	recoveryPoint := "12-31-2018 03:30 PM"
+
+	recoverJobStatusURL, err := rubrik.OracleInstantRecover(dbName, recoveryPoint)
+}
+
+func ExampleCredentials_SetGlobalBlackoutWindow() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	startTime := "22:00"
+	endTime := "02:00"
+	timezone := "America/Chicago"
+
+	blackoutWindow, err := rubrik.SetGlobalBlackoutWindow(startTime, endTime, timezone)
+}
+
+func ExampleCredentials_SetObjectBlackoutWindow() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "vm01"
+	startTime := "08:00"
+	endTime := "18:00"
+	timezone := "America/Chicago"
+
+	blackoutWindow, err := rubrik.SetObjectBlackoutWindow(vmName, "vmware", startTime, endTime, timezone)
+}
+
+func ExampleCredentials_BulkAssignSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmNames := []string{"vm01", "vm02", "vm03"}
+	slaName := "Gold"
+
+	bulkAssign, errs := rubrik.BulkAssignSLA(vmNames, "vmware", slaName)
+	for _, err := range errs {
+		log.Println(err)
+	}
+}
+
+func ExampleCredentials_TestClusterConnectivity() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	connectivity, err := rubrik.TestClusterConnectivity()
+
+	for _, check := range connectivity.Checks {
+		fmt.Printf("%s: %t %s\n", check.Name, check.Passed, check.Message)
+	}
+}
+
+func ExampleCredentials_GetNodeHardwareStatus() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	hardwareStatus, err := rubrik.GetNodeHardwareStatus()
+
+	for nodeID, status := range hardwareStatus {
+		for _, disk := range status.Disks {
+			if disk.Status != "ACTIVE" {
+				fmt.Printf("Node %s disk %s is %s\n", nodeID, disk.ID, disk.Status)
+			}
+		}
+	}
+}
+
+func ExampleCredentials_GetNodeStorageStats() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	nodeStats, err := rubrik.GetNodeStorageStats()
+
+	for _, node := range nodeStats {
+		fmt.Printf("%s: %.1f%% used\n", node.NodeID, float64(node.UsedBytes)/float64(node.CapacityBytes)*100)
+	}
+}
+
+func ExampleCredentials_VerifySLAAssignment() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "vm01"
+	expectedSLAName := "Gold"
+
+	compliant, actualSLAName, err := rubrik.VerifySLAAssignment(vmName, "vmware", expectedSLAName)
+	if compliant == false {
+		fmt.Printf("%s is protected by %s instead of %s\n", vmName, actualSLAName, expectedSLAName)
+	}
+}
+
+func ExampleCredentials_CreateWebhook() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	name := "Incident Management"
+	serverURI := "https://incidents.rubrikgo.local/api/rubrik"
+	eventTypes := []string{"Backup", "Replication"}
+	severities := []string{"Critical", "Warning"}
+	signingSecret := os.Getenv("WEBHOOK_SIGNING_SECRET")
+
+	createWebhook, err := rubrik.CreateWebhook(name, serverURI, eventTypes, severities, signingSecret)
+}
+
+func ExampleCredentials_DeleteWebhook() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	name := "Incident Management"
+
+	deleteWebhook, err := rubrik.DeleteWebhook(name)
+}
+
+func ExampleCredentials_ConfigureSNMP() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	communityString := "rubrikgo"
+
+	trapReceiver := map[string]interface{}{}
+	trapReceiver["address"] = "snmp.rubrikgo.local"
+	trapReceiver["port"] = 162
+
+	configureSNMP, err := rubrik.ConfigureSNMP(communityString, []map[string]interface{}{trapReceiver})
+}
+
+func ExampleCredentials_AddCertificate() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	certificate, err := ioutil.ReadFile("rubrik.rubrikgo.local.pem")
+	privateKey, err := ioutil.ReadFile("rubrik.rubrikgo.local.key")
+
+	addCertificate, err := rubrik.AddCertificate("rubrik-2019", string(certificate), string(privateKey))
+}
+
+func ExampleCredentials_SetWebCertificate() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	webCertificate, err := rubrik.SetWebCertificate("rubrik-2019")
+}
+
+func ExampleCredentials_SetLoginBanner() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	text := "This system is for the use of authorized users only."
+
+	loginBanner, err := rubrik.SetLoginBanner(text)
+}
+
+func ExampleCredentials_AcceptEULA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	acceptEULA, err := rubrik.AcceptEULA()
+}
+
+func ExampleCredentials_ObjectName() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmID := "VirtualMachine:::e6a7e6f1-6050-1ee33-9ba6-8e284e2801de-vm-38297"
+
+	vmName, err := rubrik.ObjectName(vmID, "vmware")
+}
+
+func ExampleClusterGroup_ForEach() {
+	clusters := rubrikcdm.NewClusterGroup(
+		rubrikcdm.ConnectAPIToken("rubrik01.rubrikgo.local", os.Getenv("RUBRIK01_TOKEN")),
+		rubrikcdm.ConnectAPIToken("rubrik02.rubrikgo.local", os.Getenv("RUBRIK02_TOKEN")),
+	)
+	clusters.Concurrency = 5
+
+	clusterErrors := clusters.ForEach(func(rubrik *rubrikcdm.Credentials) error {
+		_, err := rubrik.SetLoginBanner("This system is for the use of authorized users only.")
+		return err
+	})
+
+	for i, err := range clusterErrors {
+		log.Printf("%s: %s", clusters.Clusters[i].NodeIP, err)
+	}
+}
+
+func ExampleCredentials_GetEncryptionStatus() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	encryption, err := rubrik.GetEncryptionStatus()
+
+	fmt.Printf("Encrypted: %t (%s)\n", encryption.Enabled, encryption.Type)
+}
+
+func ExampleCredentials_GetArchivalLocationHealth() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	locationHealth, err := rubrik.GetArchivalLocationHealth()
+
+	for _, location := range locationHealth {
+		fmt.Printf("%s: %s (last upload %s)\n", location.Name, location.Status, location.LastSuccessfulUpload)
+	}
+}
+
+func ExampleCredentials_GenerateSupportBundle() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	bundleURL, err := rubrik.GenerateSupportBundle(time.Minute, 3*time.Hour)
+
+	err = rubrik.DownloadSupportBundle(bundleURL, "/tmp/rubrik-support-bundle.tar.gz")
+}
+
+func ExampleCredentials_dryRun() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	// Log the changes that would be made instead of sending them to the Rubrik cluster
+	rubrik.DryRun = true
+
+	assignSLA, err := rubrik.AssignSLA("vm01", "vmware", "Gold")
+}
+
+func ExampleCredentials_Search() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	results, err := rubrik.Search("sql01")
+
+	for _, result := range results {
+		fmt.Printf("%s %s %s\n", result.ObjectType, result.Name, result.ID)
+	}
+}
+
+func ExampleCredentials_GetEffectiveSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	slaName, inherited, err := rubrik.GetEffectiveSLA("vm01", "vmware")
+
+	fmt.Printf("Protected by %s (inherited: %t)\n", slaName, inherited)
+}
+
+func ExampleCredentials_GetUnmanagedObjects() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	unmanagedVMs, err := rubrik.GetUnmanagedObjects("vmware")
+
+	for _, vm := range unmanagedVMs {
+		fmt.Printf("%s: %d snapshots using %d bytes\n", vm.Name, vm.SnapshotCount, vm.LocalStorageBytes)
+	}
+}
+
+func ExampleCredentials_DeleteUnmanagedSnapshots() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	objectID := "VirtualMachine:::e6a7e6f1-6050-1ee33-9ba6-8e284e2801de-vm-38297"
+
+	deleteSnapshots, err := rubrik.DeleteUnmanagedSnapshots(objectID)
+}
+
+func ExampleCredentials_CloneSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	cloneSLA, err := rubrik.CloneSLA("Gold", "Gold - Finance")
+}
+
+func ExampleCredentials_SetVMwareSnapshotConsistency() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "sql01"
+	consistencyLevel := "APP_CONSISTENT"
+
+	setConsistency, err := rubrik.SetVMwareSnapshotConsistency(vmName, consistencyLevel)
+}
+
+func ExampleCredentials_SetVMwarePreBackupScript() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "app01"
+	scriptPath := "/opt/scripts/quiesce.sh"
+	failureHandling := "abort"
+	scriptTimeout := 300
+
+	preBackupScript, err := rubrik.SetVMwarePreBackupScript(vmName, scriptPath, failureHandling, scriptTimeout)
+}
+
+func ExampleCredentials_SetDefaultSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	defaultSLA, err := rubrik.SetDefaultSLA("vmware", "Bronze")
+}
+
+func ExampleCredentials_ClusterNodes() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	nodes, err := rubrik.ClusterNodes()
+
+	for _, node := range nodes {
+		fmt.Printf("%s (%s) %s %s\n", node.ID, node.BrikID, node.IPAddress, node.Status)
+	}
+}
+
+func ExampleCredentials_ExpireSnapshotsBySLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	slaName := "Gold"
+	olderThanDays := 365
+
+	expireSnapshots, err := rubrik.ExpireSnapshotsBySLA(slaName, olderThanDays)
+}
+
+func ExampleCredentials_VMwareRestore() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	vmName := "ansible-node01"
+	date := "02-16-2019"
+	snapshotTime := "01:30 PM"
+	force := true
+
+	restoreJob, err := rubrik.VMwareRestore(vmName, date, snapshotTime, force)
+}
+
+func ExampleCredentials_GetManagedVolumeChannels() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	channels, err := rubrik.GetManagedVolumeChannels("PostgreSQL")
+
+	for _, channel := range channels {
+		fmt.Println(channel)
+	}
+}
+
+func ExampleCredentials_PauseSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	pauseSLA, err := rubrik.PauseSLA("Gold")
+}
+
+func ExampleCredentials_ResumeSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	resumeSLA, err := rubrik.ResumeSLA("Gold")
+}
+
+func ExampleInt64Value() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	rubrik.PreserveNumbers = true
+
+	clusterStorage := rubrik.Get("internal", "/stats/system_storage")
+
+	totalBytes, err := rubrikcdm.Int64Value(clusterStorage.(map[string]interface{})["total"])
+}
+
+func ExampleCredentials_UpdateSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	frequencies := []rubrikcdm.SLAFrequency{
+		{TimeUnit: "Daily", Frequency: 1, Retention: 30},
+		{TimeUnit: "Monthly", Frequency: 1, Retention: 12},
+	}
+
+	updateSLA, err := rubrik.UpdateSLA("Gold", frequencies)
+}
+
+func ExampleCredentials_DeleteSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	deleteSLA, err := rubrik.DeleteSLA("Gold")
+}
+
+func ExampleCredentials_RawRequest() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	response, body, err := rubrik.RawRequest("GET", "v1", "/cluster/me", nil)
+
+	fmt.Println(response.StatusCode, response.Header.Get("Content-Type"), string(body))
+}
+
+func ExampleCredentials_ManagedVolumeSnapshot() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	mvName := "GoSDK"
+	slaName := "Gold"
+
+	snapshot, err := rubrik.ManagedVolumeSnapshot(mvName, slaName, func() error {
+		return exec.Command("/opt/scripts/backup-to-mv.sh").Run()
+	})
+}
+
+func ExampleCredentials_RefreshVMwareVM() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	refreshJob, err := rubrik.RefreshVMwareVM("ansible-node01")
+}
+
+func ExampleCredentials_GetAllVMs() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	includeRelics := false
+
+	vms, err := rubrik.GetAllVMs(includeRelics)
+
+	for _, vm := range vms {
+		fmt.Printf("%s %s %s protected=%t\n", vm.Name, vm.PowerStatus, vm.EffectiveSLADomainName, vm.Protected)
+	}
+}
+
+func ExampleCredentials_GetSnapshotWindows() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	snapshotWindows, err := rubrik.GetSnapshotWindows()
+
+	for _, window := range snapshotWindows {
+		fmt.Printf("%s %s-%s %v\n", window.SLAName, window.StartTime, window.EndTime, window.DaysOfWeek)
+	}
+}
+
+func ExampleCredentials_TestLDAPConnection() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	ldapTest, err := rubrik.TestLDAPConnection("rubrikgo.local")
+
+	if ldapTest.Connected == false {
+		fmt.Printf("The LDAP service test failed: %s\n", ldapTest.Details)
+	}
+}
+
+func ExampleCredentials_GetObjectStorageStats() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	storage, err := rubrik.GetObjectStorageStats("ansible-node01", "vmware")
+
+	fmt.Printf("%d logical bytes, %d physical bytes, %.1f%% reduction\n", storage.LogicalBytes, storage.PhysicalBytes, storage.DataReductionPercent)
+}
+
+func ExampleCredentials_GetActiveBackups() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	activeBackups, err := rubrik.GetActiveBackups()
+
+	for _, backup := range activeBackups {
+		fmt.Printf("%s %.0f%% (ETA %s)\n", backup.ObjectName, backup.ProgressPercent, backup.EstimatedCompletion.Format(time.Kitchen))
+	}
+}
+
+func ExampleCredentials_TriggerArchiveConsolidation() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+
+	consolidation, err := rubrik.TriggerArchiveConsolidation("AWS:S3:rubrik-archive")
+}
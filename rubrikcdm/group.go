@@ -0,0 +1,71 @@
+package rubrikcdm
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ClusterGroup runs the same operation against multiple Rubrik clusters. "Concurrency" limits the number of clusters the operation
+// runs against at the same time. Use 0 to run against every cluster at once.
+type ClusterGroup struct {
+	Clusters    []*Credentials
+	Concurrency int
+}
+
+// NewClusterGroup returns a ClusterGroup containing the provided "clusters" with no concurrency limit.
+func NewClusterGroup(clusters ...*Credentials) *ClusterGroup {
+	return &ClusterGroup{
+		Clusters: clusters,
+	}
+}
+
+// ForEach concurrently calls "fn" once for each cluster in the group and waits for every call to return. The result contains the
+// error returned for each cluster, keyed by the index of the cluster in "Clusters" so that clusters sharing a NodeIP are reported
+// separately, and is empty when every call succeeded. A call that panics is reported as an error for that cluster.
+func (g *ClusterGroup) ForEach(fn func(*Credentials) error) map[int]error {
+
+	concurrency := g.Concurrency
+	if concurrency <= 0 || concurrency > len(g.Clusters) {
+		concurrency = len(g.Clusters)
+	}
+
+	clusterErrors := map[int]error{}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	limit := make(chan struct{}, concurrency)
+	for i, cluster := range g.Clusters {
+		wg.Add(1)
+		limit <- struct{}{}
+
+		go func(i int, cluster *Credentials) {
+			defer wg.Done()
+			defer func() { <-limit }()
+
+			err := callClusterFunc(fn, cluster)
+			if err == nil {
+				return
+			}
+
+			mutex.Lock()
+			clusterErrors[i] = err
+			mutex.Unlock()
+		}(i, cluster)
+	}
+
+	wg.Wait()
+
+	return clusterErrors
+}
+
+// callClusterFunc calls "fn" for the "cluster" and converts a panic into an error.
+func callClusterFunc(fn func(*Credentials) error, cluster *Credentials) (err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("The operation failed against the Rubrik cluster '%s': %v", cluster.NodeIP, r)
+		}
+	}()
+
+	return fn(cluster)
+}
@@ -0,0 +1,126 @@
+package rubrikcdm
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsCollector records the outcome of every API call made to the Rubrik cluster. "endpoint" is the API version and endpoint
+// without any query parameters and with each object ID replaced by {id} (ex: v1/vmware/vm/{id}/snapshot). "statusClass" is the
+// class of the HTTP status code returned (ex: 2xx) or "error" if no response was received.
+type MetricsCollector interface {
+	RecordRequest(method, endpoint, statusClass string, duration time.Duration)
+}
+
+// NoopMetricsCollector is a MetricsCollector that discards all metrics. It is used when the Credentials "Metrics" field is nil.
+type NoopMetricsCollector struct{}
+
+// RecordRequest discards the provided metrics.
+func (NoopMetricsCollector) RecordRequest(method, endpoint, statusClass string, duration time.Duration) {
+}
+
+// RequestMetric identifies a group of API calls tracked by a RequestCounter.
+type RequestMetric struct {
+	Method      string
+	Endpoint    string
+	StatusClass string
+}
+
+// RequestStats contains the number of API calls and their combined duration for a single RequestMetric.
+type RequestStats struct {
+	Count         int
+	TotalDuration time.Duration
+}
+
+// RequestCounter is a MetricsCollector that counts API calls and their latency by method, endpoint, and status class. The zero
+// value is ready to use and it is safe for concurrent use.
+type RequestCounter struct {
+	mu    sync.Mutex
+	stats map[RequestMetric]RequestStats
+}
+
+// RecordRequest adds a single API call to the counter.
+func (r *RequestCounter) RecordRequest(method, endpoint, statusClass string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stats == nil {
+		r.stats = map[RequestMetric]RequestStats{}
+	}
+
+	metric := RequestMetric{Method: method, Endpoint: endpoint, StatusClass: statusClass}
+	stats := r.stats[metric]
+	stats.Count++
+	stats.TotalDuration += duration
+	r.stats[metric] = stats
+}
+
+// Stats returns a copy of the statistics recorded so far.
+func (r *RequestCounter) Stats() map[RequestMetric]RequestStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[RequestMetric]RequestStats, len(r.stats))
+	for metric, value := range r.stats {
+		stats[metric] = value
+	}
+
+	return stats
+}
+
+// metrics returns the MetricsCollector configured on the Credentials or a NoopMetricsCollector if none has been set.
+func (c *Credentials) metrics() MetricsCollector {
+	if c.Metrics == nil {
+		return NoopMetricsCollector{}
+	}
+	return c.Metrics
+}
+
+// metricsEndpoint removes any query parameters from the "apiEndpoint" and replaces each object ID in its path with {id} so that
+// calls to the same endpoint are grouped together (ex: v1/vmware/vm/{id}/snapshot).
+func metricsEndpoint(apiVersion, apiEndpoint string) string {
+	if i := strings.Index(apiEndpoint, "?"); i != -1 {
+		apiEndpoint = apiEndpoint[:i]
+	}
+
+	segments := strings.Split(apiEndpoint, "/")
+	for i, segment := range segments {
+		if isObjectID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return apiVersion + strings.Join(segments, "/")
+}
+
+// isObjectID determines if a path "segment" is a Rubrik object ID (ex: VirtualMachine:::{uuid}-vm-1), a UUID, or a number.
+func isObjectID(segment string) bool {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		segment = unescaped
+	}
+
+	if strings.Contains(segment, ":::") {
+		return true
+	}
+
+	hasDigit := false
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r >= 'a' && r <= 'f', r >= 'A' && r <= 'F', r == '-':
+		default:
+			return false
+		}
+	}
+
+	return hasDigit
+}
+
+// statusClass returns the class of the provided HTTP status code (ex: 2xx).
+func statusClass(statusCode int) string {
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
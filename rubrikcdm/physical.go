@@ -0,0 +1,295 @@
+package rubrikcdm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// UpdateFilesetTemplate replaces the include, exclude, and exception paths of the existing fileset template "name". The template
+// is only updated when at least one of the path lists differs from the current configuration.
+//
+// Valid "operatingSystemType" choices are:
+//
+//	Linux, Windows
+//
+// The function will return one of the following:
+//	No change required. The '{name}' Fileset Template is already configured with the provided paths.
+//
+//	The full API response for PATCH /v1/fileset_template/{id}
+func (c *Credentials) UpdateFilesetTemplate(name string, includes, excludes, exceptions []string, operatingSystemType string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(includes) == 0 {
+		return nil, errors.New("You must provide at least one path to include in the Fileset Template.")
+	}
+
+	filesetTemplateID, err := c.ObjectID(name, "filesetTemplate", operatingSystemType)
+	if err != nil {
+		return nil, err
+	}
+
+	filesetTemplate, err := c.commonAPI("GET", "v1", fmt.Sprintf("/fileset_template/%s", filesetTemplateID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if filesetPathsEqual(filesetTemplate, "includes", includes) && filesetPathsEqual(filesetTemplate, "excludes", excludes) && filesetPathsEqual(filesetTemplate, "exceptions", exceptions) {
+		return fmt.Sprintf("No change required. The '%s' Fileset Template is already configured with the provided paths.", name), nil
+	}
+
+	config := map[string]interface{}{}
+	config["includes"] = includes
+	config["excludes"] = append([]string{}, excludes...)
+	config["exceptions"] = append([]string{}, exceptions...)
+
+	return c.commonAPI("PATCH", "v1", fmt.Sprintf("/fileset_template/%s", filesetTemplateID), config, httpTimeout)
+}
+
+// filesetPathsEqual compares the "field" path list of a fileset template with the provided "paths".
+func filesetPathsEqual(filesetTemplate interface{}, field string, paths []string) bool {
+
+	currentPaths, err := getSlice(filesetTemplate, field)
+	if err != nil {
+		currentPaths = []interface{}{}
+	}
+
+	return stringEq(append([]string{}, paths...), currentPaths)
+}
+
+// SetFilesetPrePostScripts configures the scripts the Rubrik cluster runs on the host before and after each backup of a fileset created
+// from the fileset template "filesetTemplateName". An empty "preBackupScript" or "postBackupScript" removes that script from the
+// template.
+//
+// Valid "errorHandling" choices are:
+//
+//	abort, continue
+//
+// Valid "operatingSystemType" choices are:
+//
+//	Linux, Windows
+//
+// The function will return one of the following:
+//	No change required. The '{filesetTemplateName}' Fileset Template is already configured with the provided backup scripts.
+//
+//	The full API response for PATCH /v1/fileset_template/{id}
+func (c *Credentials) SetFilesetPrePostScripts(filesetTemplateName, preBackupScript, postBackupScript, errorHandling, operatingSystemType string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if errorHandling != "abort" && errorHandling != "continue" {
+		return nil, errors.New("The 'errorHandling' must be 'abort' or 'continue'.")
+	}
+
+	filesetTemplateID, err := c.ObjectID(filesetTemplateName, "filesetTemplate", operatingSystemType)
+	if err != nil {
+		return nil, err
+	}
+
+	filesetTemplate, err := c.commonAPI("GET", "v1", fmt.Sprintf("/fileset_template/%s", filesetTemplateID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	currentPreBackupScript, _ := getString(filesetTemplate, "preBackupScript")
+	currentPostBackupScript, _ := getString(filesetTemplate, "postBackupScript")
+	currentErrorHandling, _ := getString(filesetTemplate, "backupScriptErrorHandling")
+
+	if currentPreBackupScript == preBackupScript && currentPostBackupScript == postBackupScript && currentErrorHandling == errorHandling {
+		return fmt.Sprintf("No change required. The '%s' Fileset Template is already configured with the provided backup scripts.", filesetTemplateName), nil
+	}
+
+	config := map[string]string{}
+	config["preBackupScript"] = preBackupScript
+	config["postBackupScript"] = postBackupScript
+	config["backupScriptErrorHandling"] = errorHandling
+
+	return c.commonAPI("PATCH", "v1", fmt.Sprintf("/fileset_template/%s", filesetTemplateID), config, httpTimeout)
+}
+
+// AssignFilesetToHost assigns the fileset template "filesetTemplateName" to the physical host "hostName" and returns the ID of the
+// resulting fileset. If the template is already assigned to the host the ID of the existing fileset is returned.
+//
+// Valid "hostOS" choices are:
+//
+//	Linux, Windows
+func (c *Credentials) AssignFilesetToHost(hostName, filesetTemplateName, hostOS string, timeout ...int) (string, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	hostID, err := c.ObjectID(hostName, "physicalHost")
+	if err != nil {
+		return "", err
+	}
+
+	filesetTemplateID, err := c.ObjectID(filesetTemplateName, "filesetTemplate", hostOS)
+	if err != nil {
+		return "", err
+	}
+
+	hostFileset, err := c.hostFileset(hostID, filesetTemplateID, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	if hostFileset != nil {
+		return getString(hostFileset, "id")
+	}
+
+	config := map[string]string{}
+	config["hostId"] = hostID
+	config["templateId"] = filesetTemplateID
+
+	newFileset, err := c.commonAPI("POST", "v1", "/fileset", config, httpTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return getString(newFileset, "id")
+}
+
+// hostFileset returns the fileset created from the fileset template "filesetTemplateID" on the host "hostID" or nil if the template
+// is not assigned to the host.
+func (c *Credentials) hostFileset(hostID, filesetTemplateID string, timeout int) (map[string]interface{}, error) {
+
+	filesetSummary, err := c.commonAPI("GET", "v1", fmt.Sprintf("/fileset?primary_cluster_id=local&host_id=%s&is_relic=false&template_id=%s", hostID, filesetTemplateID), nil, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	filesets, err := getSlice(filesetSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filesets) == 0 {
+		return nil, nil
+	}
+
+	return getMap(filesets[0])
+}
+
+// AddNASHost registers the NAS device "hostname" with the Rubrik cluster. When provided, the "credentials" are stored on the host and
+// used to access its SMB shares. The "credentials" should be in the following format:
+//
+//	credentials := map[string]string{}
+//	credentials["username"] = "svc-rubrik"
+//	credentials["password"] = "RubrikGoRubrikGo"
+//	credentials["domain"] = "rubrikgo.local"
+//
+// The function will return one of the following:
+//	No change required. The NAS host '{hostname}' is already registered with the Rubrik cluster.
+//
+//	The full API response for POST /v1/host
+func (c *Credentials) AddNASHost(hostname string, credentials map[string]string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(credentials) != 0 && (len(credentials["username"]) == 0 || len(credentials["password"]) == 0) {
+		return nil, errors.New("The 'credentials' must contain a 'username' and 'password'.")
+	}
+
+	hostSummary, err := c.commonAPI("GET", "v1", queryEndpoint("/host", url.Values{"primary_cluster_id": {"local"}, "hostname": {hostname}}), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts, err := getSlice(hostSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		if currentHostname, _ := getString(host, "hostname"); currentHostname == hostname {
+			return fmt.Sprintf("No change required. The NAS host '%s' is already registered with the Rubrik cluster.", hostname), nil
+		}
+	}
+
+	config := map[string]interface{}{}
+	config["hostname"] = hostname
+	config["hasAgent"] = false
+
+	newHost, err := c.commonAPI("POST", "v1", "/host", config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(credentials) != 0 {
+		hostID, err := getString(newHost, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		credentialConfig := map[string]string{}
+		credentialConfig["hostId"] = hostID
+		credentialConfig["username"] = credentials["username"]
+		credentialConfig["password"] = credentials["password"]
+		if len(credentials["domain"]) != 0 {
+			credentialConfig["domain"] = credentials["domain"]
+		}
+
+		if _, err := c.commonAPI("POST", "internal", "/host/share_credential", credentialConfig, httpTimeout); err != nil {
+			return nil, fmt.Errorf("The NAS host '%s' was registered but the SMB credentials could not be added: %s", hostname, err)
+		}
+	}
+
+	return newHost, nil
+}
+
+// AddNASShare adds the "exportPoint" share of the registered NAS host "nasHostName" to the Rubrik cluster. The optional "credentials",
+// in the same format used by AddNASHost(), are only used for SMB shares and override the credentials stored on the host.
+//
+// Valid "shareType" choices are:
+//
+//	NFS, SMB
+//
+// The function will return one of the following:
+//	No change required. The {shareType} share '{exportPoint}' is already present on the NAS host '{nasHostName}'.
+//
+//	The full API response for POST /internal/host/share
+func (c *Credentials) AddNASShare(nasHostName, shareType, exportPoint string, credentials map[string]string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if shareType != "NFS" && shareType != "SMB" {
+		return nil, errors.New("The 'shareType' must be 'NFS' or 'SMB'.")
+	}
+
+	hostID, err := c.ObjectID(nasHostName, "physicalHost")
+	if err != nil {
+		return nil, err
+	}
+
+	shareSummary, err := c.commonAPI("GET", "internal", fmt.Sprintf("/host/share?host_id=%s", hostID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := getSlice(shareSummary, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, share := range shares {
+		currentExportPoint, _ := getString(share, "exportPoint")
+		currentShareType, _ := getString(share, "shareType")
+		if currentExportPoint == exportPoint && currentShareType == shareType {
+			return fmt.Sprintf("No change required. The %s share '%s' is already present on the NAS host '%s'.", shareType, exportPoint, nasHostName), nil
+		}
+	}
+
+	config := map[string]string{}
+	config["hostId"] = hostID
+	config["shareType"] = shareType
+	config["exportPoint"] = exportPoint
+	if shareType == "SMB" && len(credentials) != 0 {
+		config["username"] = credentials["username"]
+		config["password"] = credentials["password"]
+		if len(credentials["domain"]) != 0 {
+			config["domain"] = credentials["domain"]
+		}
+	}
+
+	return c.commonAPI("POST", "internal", "/host/share", config, httpTimeout)
+}
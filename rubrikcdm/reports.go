@@ -0,0 +1,247 @@
+package rubrikcdm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GetComplianceReport returns the rows of the Rubrik report named "reportName", waiting for the report data to finish updating if
+// required. Each row is keyed by the report column name (ex: ObjectName, ObjectType, SlaDomain, ComplianceStatus).
+func (c *Credentials) GetComplianceReport(reportName string, timeout ...int) ([]map[string]interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	reportID, err := c.reportID(reportName, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.waitForReport(reportID, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	return c.reportTable(reportID, nil, httpTimeout)
+}
+
+// ExportReportCSV downloads the CSV export of the Rubrik report named "reportName" and saves it to "filePath". The Rubrik cluster
+// generates the CSV asynchronously so the function will wait until the download link is available.
+func (c *Credentials) ExportReportCSV(reportName, filePath string, timeout ...int) error {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	reportID, err := c.reportID(reportName, httpTimeout)
+	if err != nil {
+		return err
+	}
+
+	if err := c.waitForReport(reportID, httpTimeout); err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < 60; attempt++ {
+		csvLink, err := c.commonAPI("GET", "internal", fmt.Sprintf("/report/%s/csv_link", reportID), nil, httpTimeout)
+		if err != nil {
+			return err
+		}
+
+		if downloadURL, ok := csvLink.(string); ok && len(downloadURL) != 0 {
+			return c.download(downloadURL, filePath, httpTimeout)
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+
+	return fmt.Errorf("Timed out waiting for the CSV download link of the report '%s'.", reportName)
+}
+
+// reportID returns the ID of the report named "reportName".
+func (c *Credentials) reportID(reportName string, timeout int) (string, error) {
+
+	apiRequest, err := c.commonAPI("GET", "internal", queryEndpoint("/report", url.Values{"name": {reportName}}), nil, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	reportSummary, _ := apiRequest.(map[string]interface{})
+	reports, ok := reportSummary["data"].([]interface{})
+	if ok != true {
+		return "", errors.New("Unable to read the reports from the Rubrik cluster.")
+	}
+
+	for _, v := range reports {
+		report, _ := v.(map[string]interface{})
+		if report["name"] == reportName {
+			reportID, _ := report["id"].(string)
+			return reportID, nil
+		}
+	}
+
+	return "", fmt.Errorf("The Rubrik cluster does not contain a report named '%s'.", reportName)
+}
+
+// waitForReport polls the report with the provided "reportID" until its data has finished updating.
+func (c *Credentials) waitForReport(reportID string, timeout int) error {
+
+	for attempt := 0; attempt < 60; attempt++ {
+		apiRequest, err := c.commonAPI("GET", "internal", fmt.Sprintf("/report/%s", reportID), nil, timeout)
+		if err != nil {
+			return err
+		}
+
+		report, _ := apiRequest.(map[string]interface{})
+		if report["updateStatus"] != "Updating" {
+			return nil
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+
+	return fmt.Errorf("Timed out waiting for the report '%s' to finish updating.", reportID)
+}
+
+// reportTable returns every row of the report with the provided "reportID", following the table cursor until all rows have been
+// read. Any "filters" (ex: objectIds) are added to the table request. Each row is keyed by the report column name.
+func (c *Credentials) reportTable(reportID string, filters map[string]interface{}, timeout int) ([]map[string]interface{}, error) {
+
+	config := map[string]interface{}{}
+	for filter, value := range filters {
+		config[filter] = value
+	}
+	config["limit"] = 1000
+
+	rows := []map[string]interface{}{}
+	for {
+		apiRequest, err := c.commonAPI("POST", "internal", fmt.Sprintf("/report/%s/table", reportID), config, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		reportTable, _ := apiRequest.(map[string]interface{})
+		tableRows, err := reportRows(reportTable)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, tableRows...)
+
+		cursor, _ := reportTable["cursor"].(string)
+		if hasMore, _ := reportTable["hasMore"].(bool); hasMore == false || len(cursor) == 0 {
+			break
+		}
+
+		config["cursor"] = cursor
+	}
+
+	return rows, nil
+}
+
+// reportRows converts the "columns" and "dataGrid" of a report table into rows keyed by the column name.
+func reportRows(reportTable map[string]interface{}) ([]map[string]interface{}, error) {
+
+	columns, ok := reportTable["columns"].([]interface{})
+	if ok != true {
+		return nil, errors.New("Unable to read the report table from the Rubrik cluster.")
+	}
+
+	rows := []map[string]interface{}{}
+	dataGrid, _ := reportTable["dataGrid"].([]interface{})
+	for _, v := range dataGrid {
+		values, _ := v.([]interface{})
+		row := map[string]interface{}{}
+		for i, column := range columns {
+			if i < len(values) {
+				row[fmt.Sprint(column)] = values[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ProtectionTask contains the details of a single backup of an object. "DataTransferred" is in bytes and "Throughput" is in
+// bytes per second.
+type ProtectionTask struct {
+	ObjectName      string
+	TaskStatus      string
+	StartTime       string
+	EndTime         string
+	Duration        time.Duration
+	DataTransferred int64
+	Throughput      float64
+}
+
+// GetProtectionTasks returns the duration, data transferred, and throughput of the most recent backups, up to "limit", of the
+// provided "objectName" using the Protection Tasks Details report data source.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, physicalHost, managedVolume
+func (c *Credentials) GetProtectionTasks(objectName, objectType string, limit int, timeout ...int) ([]ProtectionTask, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	validObjectType := map[string]bool{
+		"vmware":        true,
+		"physicalHost":  true,
+		"managedVolume": true,
+	}
+
+	if validObjectType[objectType] == false {
+		return nil, errors.New("The 'objectType' must be 'vmware', 'physicalHost', or 'managedVolume'.")
+	}
+
+	if limit <= 0 {
+		return nil, errors.New("The 'limit' must be greater than 0.")
+	}
+
+	objectID, err := c.ObjectID(objectName, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]interface{}{}
+	config["dataSource"] = "ProtectionTasksDetails"
+	config["reportTableRequest"] = map[string]interface{}{
+		"limit":     limit,
+		"sortBy":    "StartTime",
+		"sortOrder": "desc",
+		"requestFilters": map[string]interface{}{
+			"objectId": objectID,
+			"taskType": "Backup",
+		},
+	}
+
+	apiRequest, err := c.commonAPI("POST", "internal", "/report/data_source/table", config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	reportTable, _ := apiRequest.(map[string]interface{})
+	rows, err := reportRows(reportTable)
+	if err != nil {
+		return nil, err
+	}
+
+	protectionTasks := []ProtectionTask{}
+	for _, row := range rows {
+		task := ProtectionTask{}
+		task.ObjectName, _ = row["ObjectName"].(string)
+		task.TaskStatus, _ = row["TaskStatus"].(string)
+		task.StartTime, _ = row["StartTime"].(string)
+		task.EndTime, _ = row["EndTime"].(string)
+
+		// The report data source may return numeric values as strings and returns the duration in milliseconds
+		duration, _ := strconv.ParseFloat(fmt.Sprint(row["Duration"]), 64)
+		task.Duration = time.Duration(duration) * time.Millisecond
+		dataTransferred, _ := strconv.ParseFloat(fmt.Sprint(row["DataTransferred"]), 64)
+		task.DataTransferred = int64(dataTransferred)
+		task.Throughput, _ = strconv.ParseFloat(fmt.Sprint(row["Throughput"]), 64)
+
+		protectionTasks = append(protectionTasks, task)
+	}
+
+	return protectionTasks, nil
+}
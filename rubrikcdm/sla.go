@@ -0,0 +1,553 @@
+package rubrikcdm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLAFrequency defines a single snapshot frequency and retention tier of an SLA Domain. A snapshot is taken every "Frequency"
+// "TimeUnit" and retained for "Retention" "TimeUnit" (ex: a Daily tier with a Frequency of 1 and a Retention of 30 takes a
+// snapshot every day and keeps it for 30 days).
+//
+// Valid "TimeUnit" choices are:
+//
+//	Hourly, Daily, Weekly, Monthly, Yearly
+type SLAFrequency struct {
+	TimeUnit  string
+	Frequency int
+	Retention int
+}
+
+// BuildSLAFrequencies validates the provided frequency tiers and returns the "frequencies" payload used by the "apiVersion" SLA Domain
+// API. A tier may only be provided once and the retention of each tier must be at least as long as its frequency.
+//
+// Valid "apiVersion" choices are:
+//
+//	v1, v2
+func BuildSLAFrequencies(apiVersion string, frequencies ...SLAFrequency) (interface{}, error) {
+
+	if apiVersion != "v1" && apiVersion != "v2" {
+		return nil, errors.New("The 'apiVersion' must be 'v1' or 'v2'.")
+	}
+
+	if len(frequencies) == 0 {
+		return nil, errors.New("You must provide at least one SLA frequency.")
+	}
+
+	validTimeUnit := map[string]bool{
+		"Hourly":  true,
+		"Daily":   true,
+		"Weekly":  true,
+		"Monthly": true,
+		"Yearly":  true,
+	}
+
+	v1Frequencies := []map[string]interface{}{}
+	v2Frequencies := map[string]interface{}{}
+	for _, frequency := range frequencies {
+		if validTimeUnit[frequency.TimeUnit] == false {
+			return nil, fmt.Errorf("'%s' is not a valid SLA frequency time unit. The 'TimeUnit' must be 'Hourly', 'Daily', 'Weekly', 'Monthly', or 'Yearly'.", frequency.TimeUnit)
+		}
+
+		timeUnit := strings.ToLower(frequency.TimeUnit)
+		if _, ok := v2Frequencies[timeUnit]; ok {
+			return nil, fmt.Errorf("The %s SLA frequency may only be provided once.", frequency.TimeUnit)
+		}
+
+		if frequency.Frequency <= 0 || frequency.Retention <= 0 {
+			return nil, fmt.Errorf("The %s SLA frequency and retention must be greater than 0.", frequency.TimeUnit)
+		}
+
+		if frequency.Retention < frequency.Frequency {
+			return nil, fmt.Errorf("The %s SLA retention (%d) must not be shorter than its frequency (%d).", frequency.TimeUnit, frequency.Retention, frequency.Frequency)
+		}
+
+		v1Frequencies = append(v1Frequencies, map[string]interface{}{
+			"timeUnit":  frequency.TimeUnit,
+			"frequency": frequency.Frequency,
+			"retention": frequency.Retention,
+		})
+
+		v2Frequency := map[string]interface{}{
+			"frequency": frequency.Frequency,
+			"retention": frequency.Retention,
+		}
+		switch frequency.TimeUnit {
+		case "Weekly":
+			v2Frequency["dayOfWeek"] = "Saturday"
+		case "Monthly":
+			v2Frequency["dayOfMonth"] = "LastDay"
+		case "Yearly":
+			v2Frequency["dayOfYear"] = "LastDay"
+			v2Frequency["yearStartMonth"] = "January"
+		}
+		v2Frequencies[timeUnit] = v2Frequency
+	}
+
+	if apiVersion == "v1" {
+		return v1Frequencies, nil
+	}
+
+	return v2Frequencies, nil
+}
+
+// slaAPIVersion returns the version of the SLA Domain API supported by the Rubrik cluster. CDM 5.0 and later use the v2 SLA Domain
+// API, earlier releases the v1 API. The cluster version is read from the "Version" populated by Connect() when available.
+func (c *Credentials) slaAPIVersion(timeout int) (string, error) {
+
+	if len(c.Version) == 0 {
+		clusterSummary, err := c.commonAPI("GET", "v1", "/cluster/me", nil, timeout)
+		if err != nil {
+			return "", err
+		}
+
+		c.Version, err = getString(clusterSummary, "version")
+		if err != nil {
+			return "", errors.New("Unable to determine the version of the Rubrik cluster.")
+		}
+	}
+
+	majorVersion, err := strconv.Atoi(strings.SplitN(c.Version, ".", 2)[0])
+	if err != nil {
+		return "", fmt.Errorf("Unable to determine the SLA Domain API version for the Rubrik cluster version '%s'.", c.Version)
+	}
+
+	if majorVersion >= 5 {
+		return "v2", nil
+	}
+
+	return "v1", nil
+}
+
+// CreateSLA creates a new SLA Domain named "name" with the provided frequency tiers. The v1 or v2 SLA Domain API is used based on the
+// version of the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The '{name}' SLA Domain already exists on the Rubrik cluster.
+//
+//	The full API response for POST /{v1|v2}/sla_domain
+func (c *Credentials) CreateSLA(name string, frequencies []SLAFrequency, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(name) == 0 {
+		return nil, errors.New("The SLA Domain 'name' must not be a blank string.")
+	}
+
+	apiVersion, err := c.slaAPIVersion(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	slaFrequencies, err := BuildSLAFrequencies(apiVersion, frequencies...)
+	if err != nil {
+		return nil, err
+	}
+
+	slaExists, err := c.slaExists(name, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if slaExists {
+		return fmt.Sprintf("No change required. The '%s' SLA Domain already exists on the Rubrik cluster.", name), nil
+	}
+
+	config := map[string]interface{}{}
+	config["name"] = name
+	config["frequencies"] = slaFrequencies
+
+	return c.commonAPI("POST", apiVersion, "/sla_domain", config, httpTimeout)
+}
+
+// UpdateSLA replaces the frequency tiers of the existing "name" SLA Domain with the provided "frequencies". The v1 or v2 SLA Domain
+// API is used based on the version of the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The '{name}' SLA Domain is already configured with the provided frequencies.
+//
+//	The full API response for PATCH /v1/sla_domain/{slaID} or PUT /v2/sla_domain/{slaID}
+func (c *Credentials) UpdateSLA(name string, frequencies []SLAFrequency, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	apiVersion, err := c.slaAPIVersion(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	slaFrequencies, err := BuildSLAFrequencies(apiVersion, frequencies...)
+	if err != nil {
+		return nil, err
+	}
+
+	slaID, err := c.ObjectID(name, "sla")
+	if err != nil {
+		return nil, err
+	}
+
+	slaSummary, err := c.commonAPI("GET", apiVersion, fmt.Sprintf("/sla_domain/%s", slaID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	currentFrequencies, _ := apiValue(slaSummary, []string{"frequencies"})
+	if slaFrequenciesEqual(currentFrequencies, slaFrequencies) {
+		return fmt.Sprintf("No change required. The '%s' SLA Domain is already configured with the provided frequencies.", name), nil
+	}
+
+	if apiVersion == "v1" {
+		config := map[string]interface{}{}
+		config["frequencies"] = slaFrequencies
+
+		return c.commonAPI("PATCH", "v1", fmt.Sprintf("/sla_domain/%s", slaID), config, httpTimeout)
+	}
+
+	// The v2 API replaces the entire SLA Domain so the current configuration is submitted with the new frequencies
+	config, err := slaConfig(slaSummary)
+	if err != nil {
+		return nil, err
+	}
+	config["frequencies"] = slaFrequencies
+
+	return c.commonAPI("PUT", "v2", fmt.Sprintf("/sla_domain/%s", slaID), config, httpTimeout)
+}
+
+// slaFrequenciesEqual determines if the "current" frequencies of an SLA Domain match the "desired" frequencies returned by
+// BuildSLAFrequencies(). Fields that are not part of the "desired" frequencies, such as those added by the Rubrik cluster, are ignored.
+func slaFrequenciesEqual(current, desired interface{}) bool {
+
+	currentFrequencies, err := slaFrequencyTiers(current)
+	if err != nil {
+		return false
+	}
+
+	desiredFrequencies, err := slaFrequencyTiers(desired)
+	if err != nil || len(currentFrequencies) != len(desiredFrequencies) {
+		return false
+	}
+
+	for timeUnit, desiredFrequency := range desiredFrequencies {
+		currentFrequency, ok := currentFrequencies[timeUnit]
+		if ok != true {
+			return false
+		}
+
+		for field, value := range desiredFrequency {
+			if fmt.Sprint(currentFrequency[field]) != fmt.Sprint(value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// slaFrequencyTiers converts v1 (list) or v2 (map) SLA Domain frequencies into a map of the frequency settings of each time unit.
+func slaFrequencyTiers(frequencies interface{}) (map[string]map[string]interface{}, error) {
+
+	var converted interface{}
+	if err := convertResponse(frequencies, &converted); err != nil {
+		return nil, err
+	}
+
+	tiers := map[string]map[string]interface{}{}
+	switch converted := converted.(type) {
+	case []interface{}:
+		for _, v := range converted {
+			frequency, ok := v.(map[string]interface{})
+			if ok != true {
+				return nil, errors.New("The SLA Domain frequencies are not in a valid format.")
+			}
+			tiers[strings.ToLower(fmt.Sprint(frequency["timeUnit"]))] = frequency
+		}
+	case map[string]interface{}:
+		for timeUnit, v := range converted {
+			frequency, ok := v.(map[string]interface{})
+			if ok != true {
+				return nil, errors.New("The SLA Domain frequencies are not in a valid format.")
+			}
+			tiers[timeUnit] = frequency
+		}
+	default:
+		return nil, errors.New("The SLA Domain frequencies are not in a valid format.")
+	}
+
+	return tiers, nil
+}
+
+// DeleteSLA deletes the "name" SLA Domain from the Rubrik cluster. The v1 or v2 SLA Domain API is used based on the version of the
+// Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The '{name}' SLA Domain does not exist on the Rubrik cluster.
+//
+//	The full API response for DELETE /{v1|v2}/sla_domain/{slaID}
+func (c *Credentials) DeleteSLA(name string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	slaExists, err := c.slaExists(name, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if slaExists == false {
+		return fmt.Sprintf("No change required. The '%s' SLA Domain does not exist on the Rubrik cluster.", name), nil
+	}
+
+	apiVersion, err := c.slaAPIVersion(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	slaID, err := c.ObjectID(name, "sla")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.commonAPI("DELETE", apiVersion, fmt.Sprintf("/sla_domain/%s", slaID), nil, httpTimeout)
+}
+
+// slaExists determines if an SLA Domain named "name" exists on the Rubrik cluster.
+func (c *Credentials) slaExists(name string, timeout int) (bool, error) {
+
+	slaSummary, err := c.commonAPI("GET", "v1", queryEndpoint("/sla_domain", url.Values{"primary_cluster_id": {"local"}, "name": {name}}), nil, timeout)
+	if err != nil {
+		return false, err
+	}
+
+	slaDomains, err := getSlice(slaSummary, "data")
+	if err != nil {
+		return false, err
+	}
+
+	for _, slaDomain := range slaDomains {
+		if slaName, _ := getString(slaDomain, "name"); slaName == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CloneSLA creates a new SLA Domain named "newName" with the same frequencies, backup windows, archival, and replication
+// configuration as the existing "sourceName" SLA Domain. The v1 or v2 SLA Domain API is used based on the version of the Rubrik cluster.
+//
+// The function will return one of the following:
+//	No change required. The '{newName}' SLA Domain already exists on the Rubrik cluster.
+//
+//	The full API response for POST /{v1|v2}/sla_domain
+func (c *Credentials) CloneSLA(sourceName, newName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	if len(newName) == 0 {
+		return nil, errors.New("The SLA Domain 'newName' must not be a blank string.")
+	}
+
+	slaExists, err := c.slaExists(newName, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if slaExists {
+		return fmt.Sprintf("No change required. The '%s' SLA Domain already exists on the Rubrik cluster.", newName), nil
+	}
+
+	sourceID, err := c.ObjectID(sourceName, "sla")
+	if err != nil {
+		return nil, err
+	}
+
+	apiVersion, err := c.slaAPIVersion(httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceSLA, err := c.commonAPI("GET", apiVersion, fmt.Sprintf("/sla_domain/%s", sourceID), nil, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := slaConfig(sourceSLA)
+	if err != nil {
+		return nil, err
+	}
+	config["name"] = newName
+
+	return c.commonAPI("POST", apiVersion, "/sla_domain", config, httpTimeout)
+}
+
+// slaConfig returns the configuration of an SLA Domain summary without the fields that are managed by the Rubrik cluster.
+func slaConfig(slaSummary interface{}) (map[string]interface{}, error) {
+
+	summary, err := getMap(slaSummary)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]interface{}{}
+	for field, value := range summary {
+		// Remove the ID, links, and protected object counts (ex: numVms) that the Rubrik cluster manages
+		if field == "id" || field == "primaryClusterId" || field == "links" || field == "isDefault" || strings.HasPrefix(field, "num") {
+			continue
+		}
+		config[field] = value
+	}
+
+	return config, nil
+}
+
+// PauseSLA pauses protection for every object protected by the "slaName" SLA Domain. New snapshots are not taken for the objects
+// until the SLA Domain is resumed.
+//
+// The function will return one of the following:
+//	No change required. The '{slaName}' SLA Domain is already paused.
+//
+//	The full API response for POST /v2/sla_domain/{slaID}/pause
+func (c *Credentials) PauseSLA(slaName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.setSLAPause(slaName, true, httpTimeout)
+}
+
+// ResumeSLA resumes protection for every object protected by the "slaName" SLA Domain.
+//
+// The function will return one of the following:
+//	No change required. The '{slaName}' SLA Domain is currently not paused.
+//
+//	The full API response for POST /v2/sla_domain/{slaID}/pause
+func (c *Credentials) ResumeSLA(slaName string, timeout ...int) (interface{}, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	return c.setSLAPause(slaName, false, httpTimeout)
+}
+
+// setSLAPause pauses or resumes the "slaName" SLA Domain when it is not already in the requested state.
+func (c *Credentials) setSLAPause(slaName string, paused bool, timeout int) (interface{}, error) {
+
+	slaID, err := c.ObjectID(slaName, "sla")
+	if err != nil {
+		return nil, err
+	}
+
+	slaSummary, err := c.commonAPI("GET", "v2", fmt.Sprintf("/sla_domain/%s", slaID), nil, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if isPaused, _ := apiValue(slaSummary, []string{"isPaused"}); isPaused == paused {
+		if paused {
+			return fmt.Sprintf("No change required. The '%s' SLA Domain is already paused.", slaName), nil
+		}
+		return fmt.Sprintf("No change required. The '%s' SLA Domain is currently not paused.", slaName), nil
+	}
+
+	config := map[string]bool{}
+	config["isPaused"] = paused
+
+	return c.commonAPI("POST", "v2", fmt.Sprintf("/sla_domain/%s/pause", slaID), config, timeout)
+}
+
+// SnapshotWindow contains a window during which an SLA Domain allows snapshots to start. The "StartTime" and "EndTime" are in a
+// 24-hour HH:MM format in the time zone of the Rubrik cluster and the window applies to each of the "DaysOfWeek". "FirstFull" is
+// true for a window that only applies to the first full snapshot of a newly protected object.
+type SnapshotWindow struct {
+	SLAName       string
+	FirstFull     bool
+	StartTime     string
+	EndTime       string
+	DurationHours int
+	DaysOfWeek    []string
+}
+
+// GetSnapshotWindows returns the allowed snapshot windows configured on every SLA Domain of the Rubrik cluster. An SLA Domain without
+// any snapshot windows allows snapshots to start at any time and is not included.
+func (c *Credentials) GetSnapshotWindows(timeout ...int) ([]SnapshotWindow, error) {
+
+	httpTimeout := c.httpTimeout(timeout)
+
+	slaDomains, err := c.paginatedData("v1", queryEndpoint("/sla_domain", url.Values{"primary_cluster_id": {"local"}}), httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotWindows := []SnapshotWindow{}
+	for _, slaDomain := range slaDomains {
+		slaName, _ := getString(slaDomain, "name")
+
+		for field, firstFull := range map[string]bool{"allowedBackupWindows": false, "firstFullAllowedBackupWindows": true} {
+			windows, err := getSlice(slaDomain, field)
+			if err != nil {
+				continue
+			}
+
+			for _, window := range windows {
+				snapshotWindow, err := parseSnapshotWindow(window)
+				if err != nil {
+					return nil, fmt.Errorf("Unable to read the snapshot windows of the '%s' SLA Domain: %s", slaName, err)
+				}
+
+				snapshotWindow.SLAName = slaName
+				snapshotWindow.FirstFull = firstFull
+				snapshotWindows = append(snapshotWindows, snapshotWindow)
+			}
+		}
+	}
+
+	sort.SliceStable(snapshotWindows, func(i, j int) bool {
+		if snapshotWindows[i].SLAName != snapshotWindows[j].SLAName {
+			return snapshotWindows[i].SLAName < snapshotWindows[j].SLAName
+		}
+		return snapshotWindows[i].FirstFull == false && snapshotWindows[j].FirstFull
+	})
+
+	return snapshotWindows, nil
+}
+
+// parseSnapshotWindow converts an allowed backup window of an SLA Domain into a SnapshotWindow. A window without a "dayOfWeek" (1 for
+// Sunday through 7 for Saturday) applies to every day of the week.
+func parseSnapshotWindow(window interface{}) (SnapshotWindow, error) {
+
+	startHour, _ := apiValue(window, []string{"startTimeAttributes", "hour"})
+	hour, err := Int64Value(startHour)
+	if err != nil {
+		return SnapshotWindow{}, errors.New("The snapshot window does not contain a start hour.")
+	}
+
+	startMinutes, _ := apiValue(window, []string{"startTimeAttributes", "minutes"})
+	minutes, _ := Int64Value(startMinutes)
+
+	duration, _ := apiValue(window, []string{"durationInHours"})
+	durationHours, err := Int64Value(duration)
+	if err != nil {
+		return SnapshotWindow{}, errors.New("The snapshot window does not contain a duration.")
+	}
+
+	start := time.Date(0, 1, 1, int(hour), int(minutes), 0, 0, time.UTC)
+
+	daysOfWeek := []string{}
+	startDay, _ := apiValue(window, []string{"startTimeAttributes", "dayOfWeek"})
+	if dayOfWeek, err := Int64Value(startDay); err == nil && dayOfWeek >= 1 && dayOfWeek <= 7 {
+		daysOfWeek = append(daysOfWeek, time.Weekday(dayOfWeek-1).String())
+	} else {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			daysOfWeek = append(daysOfWeek, day.String())
+		}
+	}
+
+	return SnapshotWindow{
+		StartTime:     start.Format("15:04"),
+		EndTime:       start.Add(time.Duration(durationHours) * time.Hour).Format("15:04"),
+		DurationHours: int(durationHours),
+		DaysOfWeek:    daysOfWeek,
+	}, nil
+}